@@ -3,33 +3,73 @@ package main
 //go:generate goversioninfo -manifest=downloadertube.exe.manifest -o resource_windows.syso
 
 import (
-	"fmt"
 	"os"
 
 	"github.com/diogocardoso/DownloaderTube/internal/cli"
 	"github.com/diogocardoso/DownloaderTube/internal/config"
 	"github.com/diogocardoso/DownloaderTube/internal/deps"
 	"github.com/diogocardoso/DownloaderTube/internal/downloader"
+	"github.com/diogocardoso/DownloaderTube/internal/history"
+	"github.com/diogocardoso/DownloaderTube/internal/i18n"
+	"github.com/diogocardoso/DownloaderTube/internal/subscriptions"
+	"github.com/diogocardoso/DownloaderTube/pkg/validator"
 )
 
 var version = "dev"
 
 func main() {
-	if err := deps.EnsureDependencies(); err != nil {
-		fmt.Fprintf(os.Stderr, "Erro: %v\n", err)
-		os.Exit(1)
+	cfg, cfgErr := loadConfig()
+	i18n.SetLanguage(i18n.Detect(cfg.Language))
+	if cfgErr != nil {
+		i18n.Fprintf(os.Stderr, "[AVISO] %v (usando valores padrão)\n", cfgErr)
 	}
 
-	cfg := config.New()
-
-	if err := cfg.EnsureDownloadDir(); err != nil {
-		fmt.Fprintf(os.Stderr, "Erro ao criar diretório de download: %v\n", err)
-		os.Exit(1)
-	}
+	downloader.SetTranscodeConcurrency(cfg.Transcode.Workers)
+	downloader.SetTranscodeEncoding(cfg.Transcode.Preset, cfg.Transcode.CRF)
+	downloader.SetTranscodeEnabled(!cfg.Transcode.Disabled)
 
 	ytDownloader := downloader.NewYouTube()
+	ytDownloader.Cookies = cfg.PlatformCookies(string(validator.PlatformYouTube))
+	ytDownloader.ExtractorArgs = cfg.YouTubeExtractorArgs
+	ytDownloader.Proxy = cfg.PlatformProxy(string(validator.PlatformYouTube))
 	fbDownloader := downloader.NewFacebook()
+	fbDownloader.Cookies = cfg.PlatformCookies(string(validator.PlatformFacebook))
+	fbDownloader.Proxy = cfg.PlatformProxy(string(validator.PlatformFacebook))
 	igDownloader := downloader.NewInstagram()
-	app := cli.New(cfg, ytDownloader, fbDownloader, igDownloader)
+	igDownloader.Cookies = cfg.PlatformCookies(string(validator.PlatformInstagram))
+	igDownloader.Proxy = cfg.PlatformProxy(string(validator.PlatformInstagram))
+	var hist *history.Store
+	if path, err := history.DefaultPath(); err == nil {
+		hist = history.Open(path)
+	}
+
+	var subs *subscriptions.Store
+	if path, err := subscriptions.DefaultPath(); err == nil {
+		subs = subscriptions.Open(path)
+	}
+
+	app := cli.New(cfg, hist, subs, ytDownloader, fbDownloader, igDownloader)
+	// As dependências só são instaladas quando um download vai acontecer; o
+	// limite lido aqui já inclui o --limit-rate do comando.
+	app.SetDependencyCheck(func() error {
+		deps.SetProxy(cfg.Proxy)
+		deps.SetRateLimit(cfg.JobRateLimit(1))
+		return deps.EnsureDependencies(cfg.YtDlpChannel)
+	})
+
+	// Sem argumentos mantém o menu interativo; com argumentos roda o modo não interativo.
+	if len(os.Args) > 1 {
+		os.Exit(app.Exec(os.Args[1:]))
+	}
 	app.Run()
 }
+
+// loadConfig lê o arquivo de configuração. Se o caminho não puder ser
+// determinado, segue só com padrões e variáveis de ambiente.
+func loadConfig() (*config.Config, error) {
+	path, err := config.DefaultPath()
+	if err != nil {
+		return config.New(), err
+	}
+	return config.Load(path)
+}
@@ -0,0 +1,369 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/diogocardoso/DownloaderTube/internal/downloader"
+	"github.com/diogocardoso/DownloaderTube/internal/i18n"
+	"github.com/diogocardoso/DownloaderTube/pkg/validator"
+)
+
+var (
+	errUnsupportedURL = i18n.NewError("URL não suportada")
+	errAmbiguousURL   = i18n.NewError("URL ambígua, informe o link direto do vídeo")
+	errVideoInfo      = i18n.NewError("erro ao buscar vídeo")
+	errNoFormats      = i18n.NewError("nenhum formato de vídeo disponível")
+	errDownload       = i18n.NewError("erro no download")
+)
+
+// downloadPolicy define como escolher qualidade e idioma sem perguntar ao usuário.
+// Sem Preset nem MaxHeight, valem os padrões da configuração (ver resolveFormat).
+// Com Audio, o download é somente de áudio e a qualidade de vídeo é ignorada.
+// Os idiomas de Subtitles são resolvidos contra as legendas de cada vídeo.
+// Chapters separa o vídeo por capítulos, exceto com Audio ou Clip.
+// Items escolhe os itens de um carrossel (ex: "1,3"); vazio baixa todos.
+// ExtraLangs são outros idiomas de áudio mantidos como faixas no mesmo
+// arquivo, depois de Lang; WhatsAppCopy pede a cópia só com a faixa de Lang.
+type downloadPolicy struct {
+	Preset       downloader.QualityPreset
+	MaxHeight    int
+	Lang         string
+	ExtraLangs   []string
+	WhatsAppCopy bool
+	Audio        *downloader.AudioOptions
+	Subtitles    *downloader.SubtitleOptions
+	Clip         *downloader.ClipRange
+	Chapters     downloader.ChapterMode
+	Items        string
+
+	// retryOf, quando preenchido, liga o registro no histórico à tentativa original.
+	retryOf string
+	// filePrefix numera o arquivo pela posição na playlist (ver runPlaylist).
+	filePrefix string
+}
+
+// autoResult é o resultado de um download feito sem interação.
+type autoResult struct {
+	// Index é a posição na playlist, quando o vídeo veio de uma.
+	Index    int
+	URL      string
+	Platform validator.Platform
+	Title    string
+	Label    string
+	Height   int
+	LangCode string
+	// ExtraLangs são as faixas de áudio mantidas além de LangCode.
+	ExtraLangs []string
+	Audio      *downloader.AudioOptions
+	Clip       *downloader.ClipRange
+	Result     downloader.DownloadResult
+	Warnings   []string
+	Err        error
+}
+
+// autoDownload classifica a URL, escolhe formato e idioma pela política e baixa
+// o vídeo, desenhando o progresso em w (ou emitindo JSON, no modo --json). Os erros embrulham as sentinelas acima
+// para que o chamador saiba em qual etapa houve falha.
+func (a *App) autoDownload(rawURL string, policy downloadPolicy, w io.Writer) autoResult {
+	res := autoResult{URL: rawURL}
+
+	matches := validator.Classify(rawURL)
+	switch {
+	case len(matches) == 0:
+		res.Err = errUnsupportedURL
+		return res
+	case len(matches) > 1:
+		res.Err = errAmbiguousURL
+		return res
+	}
+	res.URL = matches[0].URL
+	res.Platform = matches[0].Platform
+	dl := a.downloaderFor(matches[0].Platform)
+	if dl == nil {
+		res.Err = errUnsupportedURL
+		return res
+	}
+
+	rec := downloadRecord{URL: res.URL, Audio: policy.Audio, Clip: policy.Clip, StartedAt: time.Now(), RetryOf: policy.retryOf}
+	defer func() {
+		rec.Title = res.Title
+		rec.Result = res.Result
+		rec.Warnings = res.Warnings
+		rec.Err = res.Err
+		if err := a.recordHistory(dl, rec); err != nil {
+			i18n.Fprintf(w, " [AVISO] Não foi possível salvar no histórico: %v\n", err)
+		}
+	}()
+
+	ctx, done := a.foregroundContext()
+	defer done()
+
+	info, err := dl.GetVideoInfo(ctx, res.URL)
+	if ctx.Err() != nil {
+		res.Err = errCanceled
+		return res
+	}
+	if err != nil {
+		res.Err = fmt.Errorf("%w: %v", errVideoInfo, err)
+		return res
+	}
+	res.Title = info.Title
+	if a.json != nil {
+		a.json.info(res.URL, string(res.Platform), info)
+	}
+
+	req := downloader.DownloadRequest{URL: res.URL, Dest: a.cfg.DownloadDir, Audio: policy.Audio, Clip: policy.Clip, FilePrefix: policy.filePrefix, RateLimit: a.cfg.JobRateLimit(1)}
+	res.Clip = policy.Clip
+	if len(info.Items) > 0 && policy.Items != "" {
+		req.Items, err = carouselSelection(policy.Items, info.Items)
+		if err != nil {
+			res.Err = err
+			return res
+		}
+		rec.Items = req.Items
+	}
+	switch {
+	case policy.Audio != nil:
+		res.Audio = policy.Audio
+		res.Label = policy.Audio.Label()
+	case carouselPhotosOnly(info.Items, req.Items):
+		res.Label = i18n.T("fotos")
+	default:
+		if len(info.Formats) == 0 {
+			res.Err = errNoFormats
+			return res
+		}
+		formatIdx, warning := a.resolveFormat(info.Formats, res.Platform, policy)
+		if warning != "" {
+			res.Warnings = append(res.Warnings, warning)
+		}
+		format := info.Formats[formatIdx]
+		res.Label = format.Label
+		res.Height = format.Height
+		req.Height = format.Height
+
+		if policy.Subtitles != nil {
+			subs, missing := resolveSubtitles(info.Subtitles, *policy.Subtitles)
+			for _, lang := range missing {
+				res.Warnings = append(res.Warnings, i18n.Sprintf("legenda %s não disponível", lang))
+			}
+			req.Subtitles = subs
+			rec.Subtitles = subs
+		}
+		if policy.Clip == nil {
+			req.Chapters = policy.Chapters
+			rec.Chapters = policy.Chapters
+		}
+	}
+	langCode, ok := pickLanguage(info.Languages, policy.Lang)
+	if !ok {
+		res.Warnings = append(res.Warnings, i18n.Sprintf("idioma %s não disponível, usado o padrão do vídeo", policy.Lang))
+	} else if strings.TrimSpace(policy.Lang) == "" {
+		langCode = preferredLanguage(info.Languages, a.cfg.PreferredLanguages, langCode)
+	}
+	res.LangCode = langCode
+	req.LangCode = langCode
+	rec.Height = res.Height
+	rec.LangCode = langCode
+	if policy.Audio == nil && langCode != "" && len(policy.ExtraLangs) > 0 {
+		if supportsMultiAudio(dl) {
+			extra, missing := pickExtraLanguages(info.Languages, policy.ExtraLangs, langCode)
+			for _, lang := range missing {
+				res.Warnings = append(res.Warnings, i18n.Sprintf("idioma %s não disponível, faixa ignorada", lang))
+			}
+			res.ExtraLangs = extra
+			req.ExtraLangs = extra
+			req.WhatsAppCopy = policy.WhatsAppCopy && len(extra) > 0
+			rec.ExtraLangs = extra
+			rec.WhatsAppCopy = req.WhatsAppCopy
+		} else {
+			res.Warnings = append(res.Warnings, i18n.T("esta plataforma mantém só uma faixa de áudio; idiomas extras ignorados"))
+		}
+	}
+
+	progress := newProgressPrinter(w)
+	if a.json != nil {
+		progress = a.json.progress(res.URL)
+	}
+
+	label := res.Label
+	if policy.Clip != nil {
+		label += ", " + policy.Clip.Label()
+	}
+	i18n.Fprintf(w, " Baixando: %s [%s]\n", info.Title, label)
+	result, err := dl.Download(ctx, req, progress)
+	fmt.Fprintln(w)
+	if isCanceled(err) {
+		res.Err = errCanceled
+		return res
+	}
+	if err != nil {
+		res.Err = fmt.Errorf("%w: %v", errDownload, err)
+		return res
+	}
+
+	res.Result = result
+	res.Warnings = append(res.Warnings, resultWarnings(result)...)
+	return res
+}
+
+// resolveFormat escolhe o formato pela política. Sem preset nem altura pedidos,
+// usa o preset padrão da plataforma e, na falta dele, a qualidade padrão da
+// configuração. Retorna um aviso quando o preset não pôde ser atendido.
+func (a *App) resolveFormat(formats []downloader.Format, platform validator.Platform, policy downloadPolicy) (int, string) {
+	preset := policy.Preset
+	if preset == "" && policy.MaxHeight <= 0 {
+		preset = a.cfg.QualityPreset(string(platform))
+	}
+	if preset == "" {
+		maxHeight := policy.MaxHeight
+		if maxHeight <= 0 {
+			maxHeight = a.cfg.DefaultQuality
+		}
+		return pickFormat(formats, maxHeight), ""
+	}
+
+	if idx, ok := preset.Resolve(formats); ok {
+		return idx, ""
+	}
+	idx := pickFormat(formats, preset.MaxHeight())
+	return idx, i18n.Sprintf("nenhuma qualidade atende ao preset %s, usado %s", preset.Label(), formats[idx].Label)
+}
+
+// resolveSubtitles casa os idiomas pedidos com as legendas do vídeo,
+// preferindo a legenda manual à automática. Retorna nil quando nenhum idioma
+// foi encontrado, junto com a lista dos que faltaram.
+func resolveSubtitles(tracks []downloader.SubtitleTrack, wanted downloader.SubtitleOptions) (*downloader.SubtitleOptions, []string) {
+	opts := downloader.SubtitleOptions{Mode: wanted.Mode}
+	var missing []string
+	for _, lang := range wanted.Langs {
+		track, ok := pickSubtitle(tracks, lang)
+		if !ok {
+			missing = append(missing, lang)
+			continue
+		}
+		opts.Langs = append(opts.Langs, track.Lang)
+		opts.Auto = opts.Auto || track.Auto
+	}
+	if len(opts.Langs) == 0 {
+		return nil, missing
+	}
+	return &opts, missing
+}
+
+// pickSubtitle procura a legenda por idioma exato e depois pelo idioma base
+// (ex: "pt" casa com "pt-BR"); as manuais vêm antes na lista.
+func pickSubtitle(tracks []downloader.SubtitleTrack, wanted string) (downloader.SubtitleTrack, bool) {
+	wanted = strings.TrimSpace(wanted)
+	for _, t := range tracks {
+		if strings.EqualFold(t.Lang, wanted) {
+			return t, true
+		}
+	}
+	base := strings.SplitN(wanted, "-", 2)[0]
+	for _, t := range tracks {
+		if strings.EqualFold(strings.SplitN(t.Lang, "-", 2)[0], base) {
+			return t, true
+		}
+	}
+	return downloader.SubtitleTrack{}, false
+}
+
+// pickFormat retorna o índice do maior formato com altura <= maxHeight.
+// Com maxHeight <= 0 retorna o melhor formato; se nenhum couber, retorna o menor.
+func pickFormat(formats []downloader.Format, maxHeight int) int {
+	best := -1
+	for i, f := range formats {
+		if maxHeight > 0 && f.Height > maxHeight {
+			continue
+		}
+		if best < 0 || f.Height > formats[best].Height {
+			best = i
+		}
+	}
+	if best >= 0 {
+		return best
+	}
+
+	lowest := 0
+	for i, f := range formats {
+		if f.Height < formats[lowest].Height {
+			lowest = i
+		}
+	}
+	return lowest
+}
+
+// supportsMultiAudio indica se dl mantém várias faixas de áudio no mesmo
+// arquivo (ver downloader.MultiAudioDownloader).
+func supportsMultiAudio(dl downloader.Downloader) bool {
+	m, ok := dl.(downloader.MultiAudioDownloader)
+	return ok && m.SupportsMultiAudio()
+}
+
+// preferredLanguage aplica os idiomas preferidos da configuração, em ordem,
+// quando nenhum idioma foi pedido. Idiomas preferidos ausentes no vídeo são
+// ignorados sem aviso e fallback é mantido.
+func preferredLanguage(languages []downloader.AudioLang, preferred []string, fallback string) string {
+	if len(languages) == 0 {
+		return fallback
+	}
+	for _, p := range preferred {
+		if code, ok := pickLanguage(languages, p); ok && code != "" {
+			return code
+		}
+	}
+	return fallback
+}
+
+// pickExtraLanguages resolve os idiomas das faixas extras, sem repetir o
+// principal. Retorna também os pedidos que não existem no vídeo.
+func pickExtraLanguages(languages []downloader.AudioLang, wanted []string, main string) ([]string, []string) {
+	var extra, missing []string
+	for _, w := range wanted {
+		code, ok := pickLanguage(languages, w)
+		switch {
+		case !ok:
+			missing = append(missing, w)
+		case code != "" && code != main && !slices.Contains(extra, code):
+			extra = append(extra, code)
+		}
+	}
+	return extra, missing
+}
+
+// pickLanguage resolve o idioma pedido contra os idiomas disponíveis.
+// Aceita correspondência exata ou pelo idioma base (ex: "pt" casa com "pt-BR").
+// Retorna ok=false quando o idioma pedido não existe no vídeo.
+func pickLanguage(languages []downloader.AudioLang, wanted string) (string, bool) {
+	wanted = strings.TrimSpace(wanted)
+	if wanted == "" {
+		if len(languages) == 1 {
+			return languages[0].Code, true
+		}
+		return "", true
+	}
+	if len(languages) == 0 {
+		// Sem lista de idiomas: deixa o filtro de formato do yt-dlp decidir.
+		return wanted, true
+	}
+
+	for _, l := range languages {
+		if strings.EqualFold(l.Code, wanted) {
+			return l.Code, true
+		}
+	}
+
+	base := strings.SplitN(wanted, "-", 2)[0]
+	for _, l := range languages {
+		if strings.EqualFold(strings.SplitN(l.Code, "-", 2)[0], base) {
+			return l.Code, true
+		}
+	}
+
+	return "", false
+}
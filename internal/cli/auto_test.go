@@ -0,0 +1,82 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/diogocardoso/DownloaderTube/internal/config"
+	"github.com/diogocardoso/DownloaderTube/internal/downloader"
+	"github.com/diogocardoso/DownloaderTube/pkg/validator"
+)
+
+func TestResolveFormatPrecedence(t *testing.T) {
+	formats := []downloader.Format{{Height: 1080, Label: "1080p"}, {Height: 720, Label: "720p"}, {Height: 360, Label: "360p"}}
+	cfg := config.New()
+	cfg.DefaultQuality = 720
+	cfg.QualityPresets = map[string]string{"instagram": "whatsapp"}
+	a := &App{cfg: cfg}
+
+	cases := []struct {
+		name     string
+		platform validator.Platform
+		policy   downloadPolicy
+		want     int
+	}{
+		{"preset pedido vence tudo", validator.PlatformInstagram, downloadPolicy{Preset: downloader.PresetBest, MaxHeight: 360}, 1080},
+		{"altura pedida vence o preset da plataforma", validator.PlatformInstagram, downloadPolicy{MaxHeight: 1080}, 1080},
+		{"preset da plataforma", validator.PlatformInstagram, downloadPolicy{}, 360},
+		{"qualidade padrão sem preset da plataforma", validator.PlatformYouTube, downloadPolicy{}, 720},
+	}
+	for _, c := range cases {
+		idx, warning := a.resolveFormat(formats, c.platform, c.policy)
+		if formats[idx].Height != c.want || warning != "" {
+			t.Errorf("%s: esperava %dp, veio %dp (aviso %q)", c.name, c.want, formats[idx].Height, warning)
+		}
+	}
+
+	idx, warning := a.resolveFormat(formats[:1], validator.PlatformInstagram, downloadPolicy{})
+	if idx != 0 || warning == "" {
+		t.Errorf("preset não atendido deveria usar o formato disponível com aviso, veio idx=%d aviso=%q", idx, warning)
+	}
+}
+
+func TestPickFormatHeightFallback(t *testing.T) {
+	formats := []downloader.Format{{Height: 720}, {Height: 1080}, {Height: 360}, {Height: 480}}
+
+	cases := []struct {
+		name      string
+		maxHeight int
+		want      int
+	}{
+		{"sem limite pega a maior", 0, 1080},
+		{"limite exato", 720, 720},
+		{"limite entre alturas pega a maior abaixo", 600, 480},
+		{"limite acima de todas", 2160, 1080},
+		{"limite abaixo de todas cai na menor", 240, 360},
+	}
+	for _, c := range cases {
+		if got := formats[pickFormat(formats, c.maxHeight)].Height; got != c.want {
+			t.Errorf("%s: esperava %dp, veio %dp", c.name, c.want, got)
+		}
+	}
+}
+
+func TestPickExtraLanguages(t *testing.T) {
+	languages := []downloader.AudioLang{{Code: "en"}, {Code: "pt-BR"}, {Code: "es"}}
+
+	extra, missing := pickExtraLanguages(languages, []string{"pt", "en", "fr", "es", "pt-BR"}, "en")
+	if len(extra) != 2 || extra[0] != "pt-BR" || extra[1] != "es" {
+		t.Errorf("faixas extras inesperadas: %v", extra)
+	}
+	if len(missing) != 1 || missing[0] != "fr" {
+		t.Errorf("idiomas ausentes inesperados: %v", missing)
+	}
+}
+
+func TestSupportsMultiAudio(t *testing.T) {
+	if !supportsMultiAudio(downloader.NewYouTube()) {
+		t.Errorf("YouTube deveria manter várias faixas de áudio")
+	}
+	if supportsMultiAudio(downloader.NewFacebook()) || supportsMultiAudio(downloader.NewInstagram()) {
+		t.Errorf("Facebook e Instagram mantêm só uma faixa de áudio")
+	}
+}
@@ -0,0 +1,277 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/diogocardoso/DownloaderTube/internal/downloader"
+	"github.com/diogocardoso/DownloaderTube/internal/i18n"
+)
+
+func (a *App) batchMenu() {
+	for {
+		a.clearScreen()
+		i18n.Println(" Download em lote")
+		fmt.Println()
+		i18n.Println(" 1 - Ler URLs de um arquivo de texto")
+		i18n.Println(" 2 - Colar várias URLs")
+		fmt.Println()
+		i18n.Println(" 0 - Voltar")
+		i18n.Println(" x - Sair")
+		a.printSeparator()
+
+		choice := a.readInput()
+
+		var urls []string
+		switch strings.ToLower(choice) {
+		case "0":
+			return
+		case "x":
+			a.shutdown()
+			i18n.Println("\n Até logo!")
+			os.Exit(0)
+		case "1":
+			i18n.Println("\n Caminho do arquivo (uma URL por linha):")
+			path := strings.Trim(a.readInput(), `"'`)
+			var err error
+			urls, err = readURLFile(path)
+			if err != nil {
+				a.showError(err.Error())
+				continue
+			}
+		case "2":
+			i18n.Println("\n Cole as URLs, uma por linha. Linha vazia para terminar:")
+			urls = a.readPastedURLs()
+		default:
+			a.showError(i18n.T("Opção inválida!"))
+			continue
+		}
+
+		if len(urls) == 0 {
+			a.showError(i18n.T("Nenhuma URL encontrada."))
+			continue
+		}
+
+		policy, ok := a.askBatchPolicy(len(urls))
+		if !ok {
+			continue
+		}
+
+		if err := a.cfg.EnsureDownloadDir(); err != nil {
+			a.showError(i18n.Sprintf("Erro ao criar pasta de download: %v", err))
+			return
+		}
+
+		a.clearScreen()
+		results := a.runBatch(urls, policy, os.Stdout, false)
+
+		a.clearScreen()
+		printBatchSummary(os.Stdout, results)
+		a.printFooter()
+		i18n.Print("\n Pressione ENTER para continuar...")
+		a.reader.ReadString('\n')
+		return
+	}
+}
+
+// askBatchPolicy pergunta a qualidade máxima e o idioma aplicados a todo o lote.
+func (a *App) askBatchPolicy(count int) (downloadPolicy, bool) {
+	return a.askPolicy(i18n.Sprintf(" %d URL(s) para baixar.\n", count))
+}
+
+// askPolicy pergunta a qualidade e o idioma aplicados a vários downloads,
+// mostrando header no topo da tela.
+func (a *App) askPolicy(header string) (downloadPolicy, bool) {
+	for {
+		a.clearScreen()
+		fmt.Print(header)
+		fmt.Println()
+		i18n.Println(" Qualidade máxima (ex: 1080, 720, 480) ou preset (best, worst, 720p, whatsapp).")
+		i18n.Println(" Para somente áudio: mp3, m4a ou opus.")
+		if a.cfg.DefaultQuality > 0 || len(a.cfg.QualityPresets) > 0 {
+			i18n.Println(" ENTER para o padrão da configuração, 0 para voltar.")
+		} else {
+			i18n.Println(" ENTER para a melhor disponível, 0 para voltar.")
+		}
+		a.printSeparator()
+
+		input := a.readInput()
+		if input == "0" {
+			return downloadPolicy{}, false
+		}
+
+		policy, err := parseQualityChoice(input)
+		if err != nil {
+			a.showError(i18n.T("Qualidade inválida!"))
+			continue
+		}
+
+		fmt.Println()
+		i18n.Println(" Idioma do áudio preferido (ex: pt-BR, en).")
+		i18n.Println(" ENTER para o padrão de cada vídeo.")
+		policy.Lang = a.readInput()
+
+		return policy, true
+	}
+}
+
+// parseQualityChoice interpreta a qualidade digitada: um preset, uma altura
+// máxima ("720" ou "720p") ou um formato de áudio. Vazio deixa valer os
+// padrões da configuração.
+func parseQualityChoice(input string) (downloadPolicy, error) {
+	var policy downloadPolicy
+	if p, ok := downloader.ParseQualityPreset(input); ok {
+		policy.Preset = p
+	} else if f, ok := downloader.ParseAudioFormat(input); ok {
+		policy.Audio = &downloader.AudioOptions{Format: f}
+	} else if input != "" {
+		if _, err := fmt.Sscanf(strings.TrimSuffix(strings.ToLower(input), "p"), "%d", &policy.MaxHeight); err != nil || policy.MaxHeight < 0 {
+			return policy, i18n.Errorf("qualidade inválida: %s", input)
+		}
+	}
+	return policy, nil
+}
+
+func (a *App) readPastedURLs() []string {
+	var b strings.Builder
+	for {
+		line, err := a.reader.ReadString('\n')
+		if strings.TrimSpace(line) == "" {
+			break
+		}
+		b.WriteString(line)
+		b.WriteString("\n")
+		if err != nil {
+			break
+		}
+	}
+	return readURLList(strings.NewReader(b.String()))
+}
+
+func readURLFile(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, i18n.Errorf("erro ao abrir arquivo de URLs: %w", err)
+	}
+	defer f.Close()
+	return readURLList(f), nil
+}
+
+// readURLList lê URLs separadas por linha ou espaço, ignorando linhas vazias,
+// comentários iniciados por '#' e URLs repetidas.
+func readURLList(r io.Reader) []string {
+	seen := make(map[string]bool)
+	var urls []string
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		for _, field := range strings.Fields(line) {
+			if seen[field] {
+				continue
+			}
+			seen[field] = true
+			urls = append(urls, field)
+		}
+	}
+	return urls
+}
+
+// runBatch baixa as URLs em sequência com a mesma política, sem interromper o
+// lote quando um item falha. Um Ctrl+C cancela só o item atual, a menos que
+// stopOnCancel esteja ativo.
+func (a *App) runBatch(urls []string, policy downloadPolicy, w io.Writer, stopOnCancel bool) []autoResult {
+	results := make([]autoResult, 0, len(urls))
+	for i, u := range urls {
+		fmt.Fprintf(w, "\n [%d/%d] %s\n", i+1, len(urls), u)
+		res := a.autoDownload(u, policy, w)
+		if res.Err != nil {
+			i18n.Fprintf(w, " [ERRO] %v\n", res.Err)
+		}
+		if a.json != nil {
+			a.json.result(res)
+		}
+		results = append(results, res)
+		if stopOnCancel && isCanceled(res.Err) {
+			break
+		}
+	}
+	return results
+}
+
+// printBatchSummary imprime a tabela final do lote com sucessos, falhas e avisos.
+func printBatchSummary(w io.Writer, results []autoResult) {
+	var ok, warned, failed int
+
+	i18n.Fprintln(w, " Resumo do lote")
+	fmt.Fprintln(w, " -------------------------------")
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	i18n.Fprintln(tw, " #\tStatus\tVídeo\tResultado")
+	for i, r := range results {
+		name := r.Title
+		if name == "" {
+			name = r.URL
+		}
+
+		status := "OK"
+		detail := r.Result.FilePath
+		switch {
+		case isCanceled(r.Err):
+			status = i18n.T("CANCELADO")
+			detail = i18n.T("arquivos parciais removidos")
+			failed++
+		case r.Err != nil:
+			status = i18n.T("FALHA")
+			detail = r.Err.Error()
+			failed++
+		case len(r.Warnings) > 0:
+			status = i18n.T("AVISO")
+			warned++
+		default:
+			ok++
+		}
+		fmt.Fprintf(tw, " %d\t%s\t%s\t%s\n", r.number(i), status, truncate(name, 40), detail)
+	}
+	tw.Flush()
+
+	if warned > 0 {
+		fmt.Fprintln(w)
+		i18n.Fprintln(w, " Avisos:")
+		for i, r := range results {
+			if r.Err != nil {
+				continue
+			}
+			for _, warn := range r.Warnings {
+				fmt.Fprintf(w, "  #%d: %s\n", r.number(i), warn)
+			}
+		}
+	}
+
+	fmt.Fprintln(w)
+	i18n.Fprintf(w, " Sucesso: %d | Com aviso: %d | Falhas: %d\n", ok, warned, failed)
+}
+
+// number é o número da linha no resumo: a posição na playlist, quando houver,
+// ou a ordem no lote.
+func (r autoResult) number(i int) int {
+	if r.Index > 0 {
+		return r.Index
+	}
+	return i + 1
+}
+
+func truncate(s string, max int) string {
+	r := []rune(s)
+	if len(r) <= max {
+		return s
+	}
+	return string(r[:max-1]) + "…"
+}
@@ -0,0 +1,69 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/diogocardoso/DownloaderTube/internal/downloader"
+)
+
+func TestReadURLList(t *testing.T) {
+	cases := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"uma por linha", "url1\nurl2\n", "url1 url2"},
+		{"linhas vazias e espaços", "\n  url1  \n\n\t\nurl2", "url1 url2"},
+		{"comentários", "# lista\nurl1\n  # url2\nurl3", "url1 url3"},
+		{"várias na mesma linha", "url1 url2\turl3", "url1 url2 url3"},
+		{"repetidas", "url1\nurl2 url1\nurl2", "url1 url2"},
+		{"fim de linha do Windows", "url1\r\nurl2\r\n", "url1 url2"},
+		{"só comentários", "# nada\n\n", ""},
+	}
+	for _, c := range cases {
+		if got := strings.Join(readURLList(strings.NewReader(c.input)), " "); got != c.want {
+			t.Errorf("%s: veio %q, esperava %q", c.name, got, c.want)
+		}
+	}
+}
+
+func TestPrintBatchSummary(t *testing.T) {
+	results := []autoResult{
+		{URL: "url1", Title: "Primeiro", Result: downloader.DownloadResult{FilePath: "/tmp/primeiro.mp4"}},
+		{URL: "url2", Title: "Segundo", Warnings: []string{"legenda indisponível"}},
+		{URL: "url3", Err: errors.New("vídeo privado")},
+		{URL: "url4", Title: "Quarto", Err: context.Canceled},
+	}
+	var buf bytes.Buffer
+	printBatchSummary(&buf, results)
+	out := buf.String()
+
+	for _, want := range []string{
+		"/tmp/primeiro.mp4",
+		"AVISO",
+		"#2: legenda indisponível",
+		"FALHA",
+		"url3",
+		"vídeo privado",
+		"CANCELADO",
+		"Sucesso: 1 | Com aviso: 1 | Falhas: 2",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("resumo sem %q:\n%s", want, out)
+		}
+	}
+
+	playlist := []autoResult{
+		{Index: 3, Title: "Terceiro"},
+		{Index: 7, Title: "Sétimo", Warnings: []string{"sem áudio em português"}},
+	}
+	buf.Reset()
+	printBatchSummary(&buf, playlist)
+	if out := buf.String(); !strings.Contains(out, "#7: sem áudio em português") || strings.Contains(out, "#2:") {
+		t.Errorf("aviso deveria usar a posição na playlist:\n%s", out)
+	}
+}
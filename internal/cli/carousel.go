@@ -0,0 +1,102 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/diogocardoso/DownloaderTube/internal/downloader"
+	"github.com/diogocardoso/DownloaderTube/internal/i18n"
+)
+
+// selectCarouselItems mostra os itens do carrossel e lê quais baixar (ver
+// parseSelection). ENTER baixa todos e retorna nil.
+func (a *App) selectCarouselItems(info *downloader.VideoInfo) ([]int, bool) {
+	for {
+		a.clearScreen()
+		i18n.Printf(" Post: %s\n", info.Title)
+		fmt.Println()
+		i18n.Printf(" Carrossel com %d itens:\n", len(info.Items))
+		for _, it := range info.Items {
+			fmt.Printf(" %2d - %s\n", it.Index, carouselItemLabel(it))
+		}
+		fmt.Println()
+		i18n.Println(" ENTER - Todos os itens")
+		i18n.Println(" Ou escolha os itens (ex: 1,3 ou 2-4).")
+		fmt.Println()
+		i18n.Println(" 0 - Voltar")
+		a.printSeparator()
+
+		choice := a.readInput()
+		switch choice {
+		case "0":
+			return nil, false
+		case "":
+			return nil, true
+		}
+		items, err := carouselSelection(choice, info.Items)
+		if err != nil {
+			a.showError(err.Error())
+			continue
+		}
+		return items, true
+	}
+}
+
+func carouselItemLabel(it downloader.CarouselItem) string {
+	if it.Photo {
+		return i18n.T("Foto")
+	}
+	if it.Duration != "" {
+		return i18n.Sprintf("Vídeo (%s)", it.Duration)
+	}
+	return i18n.T("Vídeo")
+}
+
+// carouselSelection converte a escolha (ex: "1,3" ou "all") nas posições do
+// carrossel; a escolha de todos os itens retorna nil.
+func carouselSelection(input string, items []downloader.CarouselItem) ([]int, error) {
+	picks, err := parseSelection(input, len(items))
+	if err != nil {
+		return nil, err
+	}
+	if len(picks) == len(items) {
+		return nil, nil
+	}
+	positions := make([]int, len(picks))
+	for i, idx := range picks {
+		positions[i] = items[idx].Index
+	}
+	return positions, nil
+}
+
+// carouselPhotosOnly indica se os itens escolhidos (nil = todos) são só fotos,
+// caso em que não há qualidade de vídeo a escolher.
+func carouselPhotosOnly(items []downloader.CarouselItem, picked []int) bool {
+	if len(items) == 0 {
+		return false
+	}
+	for _, it := range items {
+		if it.Photo {
+			continue
+		}
+		if len(picked) == 0 {
+			return false
+		}
+		for _, n := range picked {
+			if n == it.Index {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// formatPositions volta as posições do carrossel ao formato de --items.
+func formatPositions(positions []int) string {
+	parts := make([]string, len(positions))
+	for i, n := range positions {
+		parts[i] = strconv.Itoa(n)
+	}
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,233 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/diogocardoso/DownloaderTube/internal/downloader"
+	"github.com/diogocardoso/DownloaderTube/internal/i18n"
+	"github.com/diogocardoso/DownloaderTube/internal/subscriptions"
+	"github.com/diogocardoso/DownloaderTube/pkg/validator"
+)
+
+// defaultCollectionLimit é o máximo de itens baixados de um perfil, da aba de
+// reels, dos stories ou de um destaque quando nenhum outro é indicado.
+const defaultCollectionLimit = 20
+
+// processCollection baixa os itens de um perfil, da aba de reels, dos stories
+// ou de um destaque do Instagram, perguntando limite, data e qualidade. Ao
+// final mostra o arquivo de cada item.
+func (a *App) processCollection(url string, dl downloader.Downloader, collector downloader.CollectionDownloader) {
+	kind := instagramKindLabel(validator.InstagramURLKind(url))
+	limit, since, ok := a.askCollectionFilters(url, kind)
+	if !ok {
+		return
+	}
+	policy, ok := a.askPolicy(i18n.Sprintf(" Instagram (%s): %s\n", kind, url))
+	if !ok {
+		return
+	}
+
+	if err := a.cfg.EnsureDownloadDir(); err != nil {
+		a.showError(i18n.Sprintf("Erro ao criar pasta de download: %v", err))
+		return
+	}
+
+	req := a.collectionRequest(url, a.platformOf(dl), policy)
+	req.Limit = limit
+	req.DateAfter = since
+
+	a.clearScreen()
+	i18n.Println(" Ctrl+C cancela o download.")
+	results := a.runCollection(url, dl, collector, req, os.Stdout)
+
+	a.clearScreen()
+	if len(results) == 0 {
+		i18n.Println(" Nenhum vídeo encontrado com esses filtros.")
+	} else {
+		printBatchSummary(os.Stdout, results)
+	}
+	a.printFooter()
+	i18n.Print("\n Pressione ENTER para continuar...")
+	a.reader.ReadString('\n')
+}
+
+// askCollectionFilters pergunta o máximo de itens e a data mínima de publicação.
+func (a *App) askCollectionFilters(url, kind string) (int, time.Time, bool) {
+	for {
+		a.clearScreen()
+		i18n.Printf(" Instagram (%s): %s\n", kind, url)
+		fmt.Println()
+		i18n.Printf(" Máximo de itens a baixar (ENTER = %d, all = sem limite):\n", defaultCollectionLimit)
+		i18n.Println(" 0 - Voltar")
+		a.printSeparator()
+
+		limit := defaultCollectionLimit
+		switch input := strings.ToLower(a.readInput()); input {
+		case "0":
+			return 0, time.Time{}, false
+		case "":
+		case "all", "todos":
+			limit = 0
+		default:
+			n, err := strconv.Atoi(input)
+			if err != nil || n < 0 {
+				a.showError(i18n.T("Número inválido!"))
+				continue
+			}
+			limit = n
+		}
+
+		fmt.Println()
+		i18n.Println(" Baixar só itens publicados a partir de (AAAA-MM-DD; ENTER = sem filtro):")
+		since, err := subscriptions.ParseDate(a.readInput())
+		if err != nil {
+			a.showError(err.Error())
+			continue
+		}
+		return limit, since, true
+	}
+}
+
+// collectionRequest traduz a política para o pedido de uma coleção. Sem
+// qualidade própria, valem o preset da plataforma e a qualidade padrão da
+// configuração, como nos downloads avulsos.
+func (a *App) collectionRequest(url string, platform validator.Platform, policy downloadPolicy) downloader.CollectionRequest {
+	req := downloader.CollectionRequest{
+		URL:       url,
+		Audio:     policy.Audio,
+		Dest:      a.cfg.DownloadDir,
+		RateLimit: a.cfg.JobRateLimit(1),
+	}
+	if policy.Audio != nil {
+		return req
+	}
+
+	preset := policy.Preset
+	if preset == "" && policy.MaxHeight == 0 {
+		preset = a.cfg.QualityPreset(string(platform))
+	}
+	switch {
+	case policy.MaxHeight > 0:
+		req.Height = policy.MaxHeight
+	case preset != "":
+		req.Height = preset.MaxHeight()
+	default:
+		req.Height = a.cfg.DefaultQuality
+	}
+	return req
+}
+
+// downloadCollection baixa os itens da coleção com o progresso em w (ou em
+// JSON) e registra cada item no histórico com o título informado. Os erros
+// embrulham as sentinelas de autoDownload.
+func (a *App) downloadCollection(dl downloader.Downloader, collector downloader.CollectionDownloader, req downloader.CollectionRequest, title string, w io.Writer) ([]downloader.DownloadResult, error) {
+	progress := newProgressPrinter(w)
+	if a.json != nil {
+		progress = a.json.progress(req.URL)
+	}
+
+	startedAt := time.Now()
+	ctx, done := a.foregroundContext()
+	items, err := collector.DownloadCollection(ctx, req, progress)
+	canceled := ctx.Err() != nil
+	done()
+	fmt.Fprintln(w)
+
+	if canceled {
+		return nil, errCanceled
+	}
+	if err != nil {
+		err = fmt.Errorf("%w: %v", errDownload, err)
+	}
+
+	for _, item := range items {
+		a.recordHistory(dl, downloadRecord{
+			URL:       req.URL,
+			Title:     title,
+			Height:    req.Height,
+			Audio:     req.Audio,
+			StartedAt: startedAt,
+			Result:    item,
+			Warnings:  resultWarnings(item),
+		})
+	}
+	return items, err
+}
+
+// runCollection baixa a coleção e descreve cada arquivo como um autoResult,
+// para reaproveitar o resumo e a saída JSON do lote. Uma falha vira um último
+// resultado com o erro.
+func (a *App) runCollection(url string, dl downloader.Downloader, collector downloader.CollectionDownloader, req downloader.CollectionRequest, w io.Writer) []autoResult {
+	platform := a.platformOf(dl)
+	label := downloader.PresetBest.Label()
+	switch {
+	case req.Audio != nil:
+		label = req.Audio.Label()
+	case req.Height > 0:
+		label = i18n.Sprintf("até %dp", req.Height)
+	}
+
+	items, err := a.downloadCollection(dl, collector, req, url, w)
+	results := make([]autoResult, 0, len(items)+1)
+	for _, item := range items {
+		results = append(results, autoResult{
+			URL:      url,
+			Platform: platform,
+			Title:    item.MediaID,
+			Label:    label,
+			Height:   req.Height,
+			Audio:    req.Audio,
+			Result:   item,
+			Warnings: resultWarnings(item),
+		})
+	}
+	if err != nil {
+		results = append(results, autoResult{URL: url, Platform: platform, Label: label, Audio: req.Audio, Err: err})
+	}
+	if a.json != nil {
+		for _, res := range results {
+			a.json.result(res)
+		}
+	}
+	return results
+}
+
+// cmdCollection é o "get" de um perfil, aba de reels, stories ou destaque:
+// baixa até limit itens publicados a partir de since e imprime o resumo como
+// o "batch".
+func (a *App) cmdCollection(url string, policy downloadPolicy, limit int, since time.Time, dl downloader.Downloader, collector downloader.CollectionDownloader, jsonOut bool) int {
+	req := a.collectionRequest(url, a.platformOf(dl), policy)
+	req.Limit = limit
+	req.DateAfter = since
+
+	var results []autoResult
+	if jsonOut {
+		a.json = newJSONWriter(os.Stdout)
+		results = a.runCollection(url, dl, collector, req, io.Discard)
+		a.json.summary(results)
+	} else {
+		results = a.runCollection(url, dl, collector, req, os.Stderr)
+		printBatchSummary(os.Stdout, results)
+	}
+	return batchExitCode(results)
+}
+
+// instagramKindLabel descreve o tipo de página para exibição.
+func instagramKindLabel(kind validator.InstagramKind) string {
+	switch kind {
+	case validator.InstagramStories:
+		return i18n.T("stories")
+	case validator.InstagramHighlight:
+		return i18n.T("destaque")
+	case validator.InstagramReels:
+		return i18n.T("reels")
+	case validator.InstagramProfile:
+		return i18n.T("perfil")
+	}
+	return i18n.T("post")
+}
@@ -0,0 +1,429 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/diogocardoso/DownloaderTube/internal/config"
+	"github.com/diogocardoso/DownloaderTube/internal/downloader"
+	"github.com/diogocardoso/DownloaderTube/internal/i18n"
+	"github.com/diogocardoso/DownloaderTube/internal/subscriptions"
+	"github.com/diogocardoso/DownloaderTube/pkg/validator"
+)
+
+// Códigos de saída do modo não interativo.
+const (
+	ExitOK             = 0
+	ExitFailure        = 1
+	ExitUsage          = 2
+	ExitUnsupportedURL = 3
+	ExitInfoFailed     = 4
+	ExitDownloadFailed = 5
+	ExitCanceled       = 130
+)
+
+// Exec executa o modo não interativo a partir dos argumentos da linha de comando
+// e retorna o código de saída do processo.
+func (a *App) Exec(args []string) int {
+	if len(args) == 0 {
+		a.printUsage(os.Stderr)
+		return ExitUsage
+	}
+
+	a.handleInterrupts()
+	switch args[0] {
+	case "get":
+		return a.cmdGet(args[1:])
+	case "batch":
+		return a.cmdBatch(args[1:])
+	case "sync":
+		return a.cmdSync(args[1:])
+	case "help", "-h", "--help":
+		a.printUsage(os.Stdout)
+		return ExitOK
+	default:
+		i18n.Fprintf(os.Stderr, "Comando desconhecido: %s\n\n", args[0])
+		a.printUsage(os.Stderr)
+		return ExitUsage
+	}
+}
+
+func (a *App) cmdGet(args []string) int {
+	fs := flag.NewFlagSet("get", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	height := fs.Int("height", 0, i18n.T("altura máxima do vídeo (ex: 720); 0 = melhor disponível"))
+	preset := fs.String("preset", "", i18n.T("preset de qualidade: best, worst, 720p ou whatsapp"))
+	lang := fs.String("lang", "", i18n.T("idioma do áudio (ex: en); vários (ex: en,pt-BR) viram faixas no mesmo arquivo, a primeira como padrão"))
+	whatsAppCopy := fs.Bool("whatsapp-copy", false, i18n.T("com vários idiomas em --lang, salva também uma cópia _whatsapp.mp4 só com o primeiro"))
+	audio := fs.String("audio", "", i18n.T("baixa somente o áudio: mp3, m4a ou opus"))
+	audioBitrate := fs.Int("audio-bitrate", 0, i18n.T("taxa máxima do áudio em kbps (com --audio); 0 = melhor disponível"))
+	subs := fs.String("subs", "", i18n.T("idiomas das legendas, separados por vírgula (ex: pt-BR,en)"))
+	subsMode := fs.String("subs-mode", string(downloader.SubtitleEmbed), i18n.T("embed (embutidas no MP4) ou srt (arquivos ao lado do vídeo)"))
+	start := fs.String("start", "", i18n.T("início do trecho a baixar (ex: 1:30 ou 1:02:03)"))
+	end := fs.String("end", "", i18n.T("fim do trecho a baixar (ex: 2:10)"))
+	chapters := fs.String("chapters", "", i18n.T("separa o vídeo por capítulos: split (só capítulos) ou both (capítulos + vídeo inteiro)"))
+	items := fs.String("items", "all", i18n.T("itens da playlist ou do carrossel a baixar (ex: 1,3,5-9 ou all)"))
+	limit := fs.Int("limit", defaultCollectionLimit, i18n.T("em perfis, reels, stories e destaques do Instagram, máximo de itens; 0 = sem limite"))
+	since := fs.String("since", "", i18n.T("em perfis, reels, stories e destaques do Instagram, só itens publicados a partir de AAAA-MM-DD"))
+	limitRate := fs.String("limit-rate", "", i18n.T("velocidade máxima do download (ex: 500K, 2M); vence a configuração"))
+	out := fs.String("out", "", i18n.Sprintf("pasta de destino (padrão: %s)", a.cfg.DownloadDir))
+	jsonOut := fs.Bool("json", false, i18n.T("emite informações, progresso e resultado como linhas JSON em stdout"))
+
+	positional, err := parseInterspersed(fs, args)
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return ExitOK
+		}
+		return ExitUsage
+	}
+	if len(positional) != 1 {
+		i18n.Fprintln(os.Stderr, "Uso: downloadertube get <url> [--height N | --preset NOME | --audio FORMATO] [--lang CODIGO] [--out PASTA] [--json]")
+		return ExitUsage
+	}
+	policy, err := commandPolicy(fs, *preset, *height, *lang)
+	policy.WhatsAppCopy = *whatsAppCopy
+	if err == nil {
+		policy.Audio, err = audioOptions(*audio, *audioBitrate)
+	}
+	if err == nil {
+		policy.Subtitles, err = subtitleOptions(*subs, *subsMode)
+	}
+	if err == nil && (*start != "" || *end != "") {
+		policy.Clip, err = downloader.ParseClipRange(*start, *end)
+	}
+	if err == nil {
+		policy.Chapters, err = chapterMode(*chapters)
+	}
+	if err == nil {
+		err = a.applyRateFlag(*limitRate)
+	}
+	policy.Items = *items
+	sinceDate, sinceErr := subscriptions.ParseDate(*since)
+	if err == nil {
+		err = sinceErr
+	}
+	if err != nil {
+		i18n.Fprintf(os.Stderr, "Erro: %v\n", err)
+		return ExitUsage
+	}
+
+	if !a.ensureDependencies() {
+		return ExitFailure
+	}
+	if *out != "" {
+		a.cfg.DownloadDir = *out
+	}
+	if err := a.cfg.EnsureDownloadDir(); err != nil {
+		i18n.Fprintf(os.Stderr, "Erro ao criar pasta de download: %v\n", err)
+		return ExitFailure
+	}
+
+	if url := validator.NormalizeURL(positional[0]); validator.IsYouTubePlaylistURL(url) {
+		if lister, ok := a.ytDownloader.(downloader.PlaylistLister); ok {
+			return a.cmdPlaylist(url, *items, policy, lister, *jsonOut)
+		}
+	}
+	if url := validator.NormalizeURL(positional[0]); validator.IsInstagramCollectionURL(url) {
+		if collector, ok := a.igDownloader.(downloader.CollectionDownloader); ok {
+			return a.cmdCollection(url, policy, *limit, sinceDate, a.igDownloader, collector, *jsonOut)
+		}
+	}
+
+	if *jsonOut {
+		a.json = newJSONWriter(os.Stdout)
+		res := a.autoDownload(positional[0], policy, io.Discard)
+		a.json.result(res)
+		if res.Err != nil {
+			return exitCodeFor(res.Err)
+		}
+		return ExitOK
+	}
+
+	res := a.autoDownload(positional[0], policy, os.Stderr)
+	for _, w := range res.Warnings {
+		i18n.Fprintf(os.Stderr, " [AVISO] %s\n", w)
+	}
+	if res.Err != nil {
+		i18n.Fprintf(os.Stderr, "Erro: %v\n", res.Err)
+		return exitCodeFor(res.Err)
+	}
+
+	if res.Result.FilePath != "" {
+		fmt.Fprintln(os.Stdout, res.Result.FilePath)
+	} else {
+		fmt.Fprintln(os.Stdout, a.cfg.DownloadDir)
+	}
+
+	return ExitOK
+}
+
+func (a *App) cmdBatch(args []string) int {
+	fs := flag.NewFlagSet("batch", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	height := fs.Int("height", 0, i18n.T("altura máxima dos vídeos (ex: 720); 0 = melhor disponível"))
+	preset := fs.String("preset", "", i18n.T("preset de qualidade: best, worst, 720p ou whatsapp"))
+	lang := fs.String("lang", "", i18n.T("idioma do áudio preferido (ex: en); vários (ex: en,pt-BR) viram faixas no mesmo arquivo, a primeira como padrão"))
+	whatsAppCopy := fs.Bool("whatsapp-copy", false, i18n.T("com vários idiomas em --lang, salva também uma cópia _whatsapp.mp4 só com o primeiro"))
+	audio := fs.String("audio", "", i18n.T("baixa somente o áudio: mp3, m4a ou opus"))
+	audioBitrate := fs.Int("audio-bitrate", 0, i18n.T("taxa máxima do áudio em kbps (com --audio); 0 = melhor disponível"))
+	subs := fs.String("subs", "", i18n.T("idiomas das legendas, separados por vírgula (ex: pt-BR,en)"))
+	subsMode := fs.String("subs-mode", string(downloader.SubtitleEmbed), i18n.T("embed (embutidas no MP4) ou srt (arquivos ao lado do vídeo)"))
+	start := fs.String("start", "", i18n.T("início do trecho a baixar (ex: 1:30 ou 1:02:03)"))
+	end := fs.String("end", "", i18n.T("fim do trecho a baixar (ex: 2:10)"))
+	chapters := fs.String("chapters", "", i18n.T("separa o vídeo por capítulos: split (só capítulos) ou both (capítulos + vídeo inteiro)"))
+	limitRate := fs.String("limit-rate", "", i18n.T("velocidade máxima do download (ex: 500K, 2M); vence a configuração"))
+	out := fs.String("out", "", i18n.Sprintf("pasta de destino (padrão: %s)", a.cfg.DownloadDir))
+	jsonOut := fs.Bool("json", false, i18n.T("emite informações, progresso e resultado de cada URL como linhas JSON em stdout"))
+
+	positional, err := parseInterspersed(fs, args)
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return ExitOK
+		}
+		return ExitUsage
+	}
+	if len(positional) != 1 {
+		i18n.Fprintln(os.Stderr, "Uso: downloadertube batch <arquivo|-> [--height N | --preset NOME | --audio FORMATO] [--lang CODIGO] [--out PASTA] [--json]")
+		return ExitUsage
+	}
+	policy, err := commandPolicy(fs, *preset, *height, *lang)
+	policy.WhatsAppCopy = *whatsAppCopy
+	if err == nil {
+		policy.Audio, err = audioOptions(*audio, *audioBitrate)
+	}
+	if err == nil {
+		policy.Subtitles, err = subtitleOptions(*subs, *subsMode)
+	}
+	if err == nil && (*start != "" || *end != "") {
+		policy.Clip, err = downloader.ParseClipRange(*start, *end)
+	}
+	if err == nil {
+		policy.Chapters, err = chapterMode(*chapters)
+	}
+	if err == nil {
+		err = a.applyRateFlag(*limitRate)
+	}
+	if err != nil {
+		i18n.Fprintf(os.Stderr, "Erro: %v\n", err)
+		return ExitUsage
+	}
+
+	var urls []string
+	if positional[0] == "-" {
+		urls = readURLList(os.Stdin)
+	} else {
+		urls, err = readURLFile(positional[0])
+		if err != nil {
+			i18n.Fprintf(os.Stderr, "Erro: %v\n", err)
+			return ExitFailure
+		}
+	}
+	if len(urls) == 0 {
+		i18n.Fprintln(os.Stderr, "Erro: nenhuma URL encontrada.")
+		return ExitUsage
+	}
+
+	if !a.ensureDependencies() {
+		return ExitFailure
+	}
+	if *out != "" {
+		a.cfg.DownloadDir = *out
+	}
+	if err := a.cfg.EnsureDownloadDir(); err != nil {
+		i18n.Fprintf(os.Stderr, "Erro ao criar pasta de download: %v\n", err)
+		return ExitFailure
+	}
+
+	var results []autoResult
+	if *jsonOut {
+		a.json = newJSONWriter(os.Stdout)
+		results = a.runBatch(urls, policy, io.Discard, true)
+		a.json.summary(results)
+	} else {
+		results = a.runBatch(urls, policy, os.Stderr, true)
+		printBatchSummary(os.Stdout, results)
+	}
+
+	return batchExitCode(results)
+}
+
+// batchExitCode resume o lote em um código de saída: cancelado vence falha.
+func batchExitCode(results []autoResult) int {
+	for _, r := range results {
+		if isCanceled(r.Err) {
+			return ExitCanceled
+		}
+		if r.Err != nil {
+			return ExitDownloadFailed
+		}
+	}
+	return ExitOK
+}
+
+// exitCodeFor traduz o erro de autoDownload para o código de saída da etapa que falhou.
+func exitCodeFor(err error) int {
+	switch {
+	case isCanceled(err):
+		return ExitCanceled
+	case errors.Is(err, errUnsupportedURL), errors.Is(err, errAmbiguousURL):
+		return ExitUnsupportedURL
+	case errors.Is(err, errVideoInfo), errors.Is(err, errNoFormats):
+		return ExitInfoFailed
+	case errors.Is(err, errDownload):
+		return ExitDownloadFailed
+	default:
+		return ExitFailure
+	}
+}
+
+func (a *App) printUsage(w io.Writer) {
+	fmt.Fprintf(w, "%s\n\n", a.cfg.AppName)
+	i18n.Fprintln(w, "Uso:")
+	i18n.Fprintln(w, "  downloadertube                      abre o menu interativo")
+	i18n.Fprintln(w, "  downloadertube get <url> [opções]   baixa um vídeo sem interação")
+	i18n.Fprintln(w, "  downloadertube batch <arquivo|->    baixa as URLs listadas (uma por linha)")
+	i18n.Fprintln(w, "  downloadertube sync [assinatura]    baixa os itens novos das assinaturas")
+	fmt.Fprintln(w)
+	i18n.Fprintln(w, "Opções:")
+	i18n.Fprintln(w, "  --height N      altura máxima do vídeo (ex: 720); 0 = melhor disponível")
+	i18n.Fprintln(w, "  --preset NOME   best, worst, 720p ou whatsapp (até 480p); vence --height")
+	i18n.Fprintln(w, "  --audio FORMATO somente áudio: mp3, m4a ou opus (com --audio-bitrate N em kbps)")
+	i18n.Fprintln(w, "  --lang CODIGOS  idioma do áudio (ex: en); vários (ex: en,pt-BR) viram faixas no mesmo arquivo")
+	i18n.Fprintln(w, "  --whatsapp-copy com vários idiomas, salva também uma cópia só com o primeiro")
+	i18n.Fprintln(w, "  --subs CODIGOS  legendas a baixar (ex: pt-BR,en), com --subs-mode embed ou srt")
+	i18n.Fprintln(w, "  --start/--end   baixa só o trecho entre os horários (ex: --start 1:30 --end 2:10)")
+	i18n.Fprintln(w, "  --items LISTA   em playlists e carrosséis do Instagram, os itens a baixar (ex: 1,3,5-9; padrão: all)")
+	i18n.Fprintln(w, "  --limit N       em perfis, reels, stories e destaques do Instagram, máximo de itens (padrão: 20; 0 = sem limite)")
+	i18n.Fprintln(w, "  --since DATA    em perfis, reels, stories e destaques, só itens publicados a partir de AAAA-MM-DD")
+	i18n.Fprintln(w, "  --chapters MODO um MP4 por capítulo: split (só capítulos) ou both (+ vídeo inteiro)")
+	i18n.Fprintln(w, "  --limit-rate V  velocidade máxima do download (ex: 500K, 2M)")
+	i18n.Fprintln(w, "  --out PASTA     pasta de destino")
+	i18n.Fprintln(w, "  --json          saída em linhas JSON (info, progress, result, summary)")
+	fmt.Fprintln(w)
+	if path := a.cfg.Path(); path != "" {
+		i18n.Fprintf(w, "Configuração: %s\n", path)
+	}
+	i18n.Fprintln(w, "  precedência: opções > variáveis DT_* > arquivo > padrões")
+	fmt.Fprintln(w)
+	i18n.Fprintln(w, "Códigos de saída:")
+	i18n.Fprintln(w, "  0 sucesso | 1 erro geral | 2 uso incorreto | 3 URL não suportada")
+	i18n.Fprintln(w, "  4 falha ao obter informações | 5 falha no download | 130 cancelado (Ctrl+C)")
+}
+
+// applyRateFlag faz --limit-rate valer como limite único desta execução, no
+// lugar dos limites global e por download da configuração.
+func (a *App) applyRateFlag(raw string) error {
+	if raw == "" {
+		return nil
+	}
+	if _, err := downloader.ParseRate(raw); err != nil {
+		return err
+	}
+	a.cfg.RateLimit = raw
+	a.cfg.RateLimitPerJob = ""
+	return nil
+}
+
+// commandPolicy monta a política a partir das opções. --height só conta quando
+// informado, para que a ausência dele deixe valer os padrões da configuração.
+func commandPolicy(fs *flag.FlagSet, preset string, height int, lang string) (downloadPolicy, error) {
+	policy := downloadPolicy{}
+	// "--lang en,pt-BR": o primeiro é a faixa padrão, os outros faixas extras.
+	for _, code := range strings.Split(lang, ",") {
+		if code = strings.TrimSpace(code); code == "" {
+			continue
+		}
+		if policy.Lang == "" {
+			policy.Lang = code
+		} else {
+			policy.ExtraLangs = append(policy.ExtraLangs, code)
+		}
+	}
+	if preset != "" {
+		p, ok := downloader.ParseQualityPreset(preset)
+		if !ok {
+			return policy, i18n.Errorf("preset desconhecido: %s", preset)
+		}
+		policy.Preset = p
+		return policy, nil
+	}
+
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == "height" && height <= 0 {
+			policy.Preset = downloader.PresetBest
+		}
+	})
+	policy.MaxHeight = height
+	return policy, nil
+}
+
+// audioOptions valida --audio e --audio-bitrate. Sem --audio o download é de
+// vídeo e retorna nil.
+func audioOptions(format string, bitrate int) (*downloader.AudioOptions, error) {
+	if format == "" {
+		if bitrate != 0 {
+			return nil, i18n.Errorf("--audio-bitrate exige --audio")
+		}
+		return nil, nil
+	}
+	f, ok := downloader.ParseAudioFormat(format)
+	if !ok {
+		return nil, i18n.Errorf("formato de áudio desconhecido: %s", format)
+	}
+	if bitrate < 0 {
+		return nil, i18n.Errorf("taxa de áudio inválida: %d", bitrate)
+	}
+	return &downloader.AudioOptions{Format: f, Bitrate: bitrate}, nil
+}
+
+// subtitleOptions valida --subs e --subs-mode. Sem --subs não há legendas e
+// retorna nil.
+func subtitleOptions(langs, mode string) (*downloader.SubtitleOptions, error) {
+	m, ok := downloader.ParseSubtitleMode(mode)
+	if !ok {
+		return nil, i18n.Errorf("modo de legenda desconhecido: %s", mode)
+	}
+	list := config.SplitList(langs)
+	if len(list) == 0 {
+		return nil, nil
+	}
+	return &downloader.SubtitleOptions{Langs: list, Mode: m}, nil
+}
+
+// chapterMode valida --chapters; vazio mantém só o vídeo inteiro.
+func chapterMode(name string) (downloader.ChapterMode, error) {
+	if name == "" {
+		return "", nil
+	}
+	mode, ok := downloader.ParseChapterMode(name)
+	if !ok {
+		return "", i18n.Errorf("modo de capítulos desconhecido: %s", name)
+	}
+	return mode, nil
+}
+
+// parseInterspersed permite flags antes ou depois dos argumentos posicionais
+// (ex: "get <url> --height 720"), o que o pacote flag não faz sozinho. Tudo
+// depois de "--" é posicional.
+func parseInterspersed(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		rest := fs.Args()
+		if consumed := len(args) - len(rest); consumed > 0 && args[consumed-1] == "--" {
+			return append(positional, rest...), nil
+		}
+		args = rest
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
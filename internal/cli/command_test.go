@@ -0,0 +1,50 @@
+package cli
+
+import (
+	"flag"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestParseInterspersed(t *testing.T) {
+	cases := []struct {
+		name       string
+		args       []string
+		positional string
+		height     int
+		audio      bool
+		wantErr    bool
+	}{
+		{"flags antes da URL", []string{"--height", "720", "--audio", "url1"}, "url1", 720, true, false},
+		{"flags depois da URL", []string{"url1", "--height", "720", "--audio"}, "url1", 720, true, false},
+		{"flags entre as URLs", []string{"url1", "-height=480", "url2", "--audio", "url3"}, "url1 url2 url3", 480, true, false},
+		{"sem flags", []string{"url1", "url2"}, "url1 url2", 0, false, false},
+		{"tudo depois de -- é posicional", []string{"--audio", "url1", "--", "-x", "--height", "720"}, "url1 -x --height 720", 0, true, false},
+		{"-- no começo", []string{"--", "-x"}, "-x", 0, false, false},
+		{"sem argumentos", nil, "", 0, false, false},
+		{"flag desconhecida", []string{"url1", "--altura", "720"}, "", 0, false, true},
+		{"valor inválido", []string{"url1", "--height", "alto"}, "", 0, false, true},
+	}
+	for _, c := range cases {
+		fs := flag.NewFlagSet("get", flag.ContinueOnError)
+		fs.SetOutput(io.Discard)
+		height := fs.Int("height", 0, "")
+		audio := fs.Bool("audio", false, "")
+
+		positional, err := parseInterspersed(fs, c.args)
+		if (err != nil) != c.wantErr {
+			t.Errorf("%s: erro inesperado: %v", c.name, err)
+			continue
+		}
+		if c.wantErr {
+			continue
+		}
+		if got := strings.Join(positional, " "); got != c.positional {
+			t.Errorf("%s: posicionais = %q, esperava %q", c.name, got, c.positional)
+		}
+		if *height != c.height || *audio != c.audio {
+			t.Errorf("%s: height=%d audio=%v, esperava %d e %v", c.name, *height, *audio, c.height, c.audio)
+		}
+	}
+}
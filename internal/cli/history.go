@@ -0,0 +1,501 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/diogocardoso/DownloaderTube/internal/downloader"
+	"github.com/diogocardoso/DownloaderTube/internal/history"
+	"github.com/diogocardoso/DownloaderTube/internal/i18n"
+	"github.com/diogocardoso/DownloaderTube/pkg/validator"
+)
+
+const historyPageSize = 15
+
+// downloadRecord reúne os dados de uma tentativa de download para o histórico.
+type downloadRecord struct {
+	URL      string
+	Title    string
+	Height   int
+	LangCode string
+	// ExtraLangs e WhatsAppCopy repetem os campos de DownloadRequest.
+	ExtraLangs   []string
+	WhatsAppCopy bool
+	Audio        *downloader.AudioOptions
+	Subtitles    *downloader.SubtitleOptions
+	Clip         *downloader.ClipRange
+	Chapters     downloader.ChapterMode
+	Items        []int
+	StartedAt    time.Time
+	Result       downloader.DownloadResult
+	Warnings     []string
+	Err          error
+	RetryOf      string
+}
+
+// recordHistory grava a tentativa no histórico. Falhas ao gravar não devem
+// interromper o download, então apenas retornam o erro para exibição opcional.
+func (a *App) recordHistory(dl downloader.Downloader, rec downloadRecord) error {
+	// Cancelamentos são decisão do usuário, não falhas a repetir depois.
+	if a.history == nil || isCanceled(rec.Err) {
+		return nil
+	}
+
+	entry := history.Entry{
+		URL:          rec.URL,
+		Platform:     string(a.platformOf(dl)),
+		MediaID:      rec.Result.MediaID,
+		Title:        rec.Title,
+		Height:       rec.Height,
+		LangCode:     rec.LangCode,
+		FilePath:     rec.Result.FilePath,
+		Warnings:     rec.Warnings,
+		StartedAt:    rec.StartedAt,
+		FinishedAt:   time.Now(),
+		Success:      rec.Err == nil,
+		RetryOf:      rec.RetryOf,
+		ChapterMode:  string(rec.Chapters),
+		Files:        rec.Result.Files,
+		Items:        rec.Items,
+		ExtraLangs:   rec.ExtraLangs,
+		WhatsAppCopy: rec.WhatsAppCopy,
+	}
+	if rec.Audio != nil {
+		entry.AudioFormat = string(rec.Audio.Format)
+		entry.AudioBitrate = rec.Audio.Bitrate
+	}
+	if rec.Clip != nil {
+		entry.ClipStart = rec.Clip.Start
+		entry.ClipEnd = rec.Clip.End
+	}
+	if rec.Subtitles != nil {
+		entry.SubtitleLangs = rec.Subtitles.Langs
+		entry.SubtitleAuto = rec.Subtitles.Auto
+		entry.SubtitleMode = string(rec.Subtitles.Mode)
+	}
+	if rec.Err != nil {
+		entry.Error = rec.Err.Error()
+	}
+
+	_, err := a.history.Add(entry)
+	return err
+}
+
+// resultWarnings converte os avisos do resultado em mensagens para exibição e histórico.
+func resultWarnings(result downloader.DownloadResult) []string {
+	var warnings []string
+	if result.CompatibilityWarning != "" {
+		warnings = append(warnings, i18n.Sprintf("Compatibilidade WhatsApp: %s", result.CompatibilityWarning))
+	}
+	if result.SubtitleWarning != "" {
+		warnings = append(warnings, i18n.Sprintf("Legendas: %s", result.SubtitleWarning))
+	}
+	if result.ChapterWarning != "" {
+		warnings = append(warnings, i18n.Sprintf("Capítulos: %s", result.ChapterWarning))
+	}
+	if result.ItemWarning != "" {
+		warnings = append(warnings, i18n.Sprintf("Carrossel: %s", result.ItemWarning))
+	}
+	return warnings
+}
+
+func (a *App) historyMenu() {
+	if a.history == nil {
+		a.showError(i18n.T("Histórico indisponível: não foi possível determinar a pasta de configuração."))
+		return
+	}
+
+	term := ""
+	page := 0
+	for {
+		entries, err := a.history.Search(term)
+		if err != nil {
+			a.showError(err.Error())
+			return
+		}
+
+		pages := (len(entries) + historyPageSize - 1) / historyPageSize
+		if page >= pages {
+			page = max(pages-1, 0)
+		}
+		start := page * historyPageSize
+		end := min(start+historyPageSize, len(entries))
+		visible := entries[start:end]
+
+		a.clearScreen()
+		i18n.Println(" Downloads recentes")
+		if term != "" {
+			i18n.Printf(" Busca: %q (%d resultado(s))\n", term, len(entries))
+		}
+		fmt.Println()
+
+		if len(visible) == 0 {
+			i18n.Println(" Nenhum download registrado.")
+		}
+		for i, e := range visible {
+			fmt.Printf(" %d - %s\n", i+1, formatHistoryLine(e))
+		}
+		if pages > 1 {
+			i18n.Printf("\n Página %d de %d\n", page+1, pages)
+		}
+
+		fmt.Println()
+		i18n.Println(" b - Buscar")
+		i18n.Println(" f - Repetir todas as falhas de hoje")
+		if term != "" {
+			i18n.Println(" l - Limpar busca")
+		}
+		if page+1 < pages {
+			i18n.Println(" p - Próxima página")
+		}
+		if page > 0 {
+			i18n.Println(" a - Página anterior")
+		}
+		i18n.Println(" 0 - Voltar")
+		i18n.Println(" x - Sair")
+		a.printSeparator()
+
+		choice := a.readInput()
+
+		switch strings.ToLower(choice) {
+		case "0":
+			return
+		case "x":
+			if !a.confirmExit() {
+				continue
+			}
+			a.shutdown()
+			i18n.Println("\n Até logo!")
+			os.Exit(0)
+		case "b":
+			i18n.Println("\n Termo de busca (título, URL, plataforma ou arquivo):")
+			term = a.readInput()
+			page = 0
+		case "f":
+			a.retryFailedToday()
+		case "l":
+			term = ""
+			page = 0
+		case "p":
+			if page+1 < pages {
+				page++
+			}
+		case "a":
+			if page > 0 {
+				page--
+			}
+		default:
+			idx := a.parseChoice(choice, len(visible))
+			if idx < 0 {
+				a.showError(i18n.T("Opção inválida!"))
+				continue
+			}
+			a.historyDetail(visible[idx])
+		}
+	}
+}
+
+func (a *App) historyDetail(e history.Entry) {
+	for {
+		a.clearScreen()
+		i18n.Println(" Detalhes do download")
+		a.printSeparator()
+		if e.Title != "" {
+			i18n.Printf(" Vídeo: %s\n", e.Title)
+		}
+		fmt.Printf(" URL: %s\n", e.URL)
+		i18n.Printf(" Plataforma: %s\n", e.Platform)
+		if e.MediaID != "" {
+			fmt.Printf(" ID: %s\n", e.MediaID)
+		}
+		if opts := entryAudio(e); opts != nil {
+			i18n.Printf(" Somente áudio: %s\n", opts.Label())
+		} else if e.Height > 0 {
+			i18n.Printf(" Qualidade: %dp\n", e.Height)
+		}
+		if e.LangCode != "" {
+			i18n.Printf(" Idioma: %s\n", langsLabel(e.LangCode, e.ExtraLangs))
+		}
+		if clip := entryClip(e); clip != nil {
+			i18n.Printf(" Trecho: %s\n", clip.Label())
+		}
+		if len(e.SubtitleLangs) > 0 {
+			i18n.Printf(" Legendas: %s (%s)\n", strings.Join(e.SubtitleLangs, ", "), e.SubtitleMode)
+		}
+		if mode, ok := downloader.ParseChapterMode(e.ChapterMode); ok {
+			i18n.Printf(" Capítulos: %s\n", chapterModeLabel(mode))
+		}
+		if len(e.Items) > 0 {
+			i18n.Printf(" Itens do carrossel: %s\n", formatPositions(e.Items))
+		}
+		i18n.Printf(" Início: %s\n", e.StartedAt.Local().Format("02/01/2006 15:04:05"))
+		i18n.Printf(" Fim: %s\n", e.FinishedAt.Local().Format("02/01/2006 15:04:05"))
+		if e.Success {
+			i18n.Println(" Status: concluído")
+		} else {
+			i18n.Println(" Status: falhou")
+			i18n.Printf(" Erro: %s\n", e.Error)
+		}
+		if e.FilePath != "" {
+			i18n.Printf(" Arquivo: %s\n", e.FilePath)
+		}
+		for _, f := range e.Files {
+			if e.ChapterMode != "" {
+				i18n.Printf(" Capítulo: %s\n", f)
+			} else {
+				i18n.Printf(" Arquivo: %s\n", f)
+			}
+		}
+		if e.RetryOf != "" {
+			i18n.Println(" Nova tentativa de um download que havia falhado")
+		}
+		for _, w := range e.Warnings {
+			i18n.Printf(" [AVISO] %s\n", w)
+		}
+
+		fmt.Println()
+		if e.FilePath != "" {
+			i18n.Println(" 1 - Abrir pasta do arquivo")
+		}
+		i18n.Println(" 2 - Excluir do histórico")
+		if !e.Success {
+			i18n.Println(" 3 - Tentar novamente (mesma qualidade)")
+			i18n.Println(" 4 - Tentar novamente com outra qualidade")
+		}
+		fmt.Println()
+		i18n.Println(" 0 - Voltar")
+		a.printSeparator()
+
+		choice := a.readInput()
+
+		switch strings.ToLower(choice) {
+		case "0":
+			return
+		case "1":
+			if e.FilePath == "" {
+				a.showError(i18n.T("Opção inválida!"))
+				continue
+			}
+			if err := revealFile(e.FilePath); err != nil {
+				a.showError(i18n.Sprintf("Não foi possível abrir a pasta: %v", err))
+			}
+		case "2":
+			if err := a.history.Delete(e.ID); err != nil {
+				a.showError(err.Error())
+				continue
+			}
+			return
+		case "3", "4":
+			if e.Success {
+				a.showError(i18n.T("Opção inválida!"))
+				continue
+			}
+			a.retryEntry(e, choice == "4")
+			return
+		default:
+			a.showError(i18n.T("Opção inválida!"))
+		}
+	}
+}
+
+// retryEntry repete uma tentativa do histórico com a mesma URL, plataforma,
+// qualidade e idioma. Com chooseQuality, busca os formatos e pergunta a qualidade.
+func (a *App) retryEntry(e history.Entry, chooseQuality bool) {
+	dl := a.downloaderFor(validator.Platform(e.Platform))
+	if dl == nil {
+		a.showError(i18n.T("Plataforma não suportada."))
+		return
+	}
+
+	req := downloadRequest{Title: e.Title, RetryOf: e.ID}
+	req.URL = e.URL
+	req.LangCode = e.LangCode
+	req.ExtraLangs = e.ExtraLangs
+	req.WhatsAppCopy = e.WhatsAppCopy
+	req.Subtitles = entrySubtitles(e)
+	req.Clip = entryClip(e)
+	req.Chapters, _ = downloader.ParseChapterMode(e.ChapterMode)
+	req.Items = e.Items
+	audio := entryAudio(e)
+	if audio != nil {
+		req.setAudio(*audio)
+	} else {
+		req.setFormat(downloader.Format{Height: e.Height, Label: fmt.Sprintf("%dp", e.Height)})
+	}
+
+	// Sem altura registrada a falha foi antes do download (ex: ao buscar informações),
+	// então é preciso consultar os formatos de novo. No modo somente áudio a
+	// escolha já está completa.
+	if chooseQuality || (e.Height == 0 && audio == nil) {
+		a.clearScreen()
+		i18n.Println(" Buscando informações do vídeo...")
+		a.printSeparator()
+
+		startedAt := time.Now()
+		ctx, done := a.foregroundContext()
+		info, err := dl.GetVideoInfo(ctx, e.URL)
+		done()
+		if err == nil && len(info.Formats) == 0 && len(info.AudioTracks) == 0 && !carouselPhotosOnly(info.Items, req.Items) {
+			err = errNoFormats
+		}
+		if err != nil {
+			a.recordHistory(dl, downloadRecord{URL: e.URL, Title: e.Title, LangCode: e.LangCode, Audio: audio, StartedAt: startedAt, Err: err, RetryOf: e.ID})
+			a.showError(i18n.Sprintf("Erro ao buscar vídeo: %v", err))
+			return
+		}
+
+		req.Title = info.Title
+		if carouselPhotosOnly(info.Items, req.Items) {
+			req.Label = i18n.T("fotos")
+		} else if chooseQuality {
+			if !a.selectQuality(info, &req, validator.Platform(e.Platform)) {
+				return
+			}
+		} else if len(info.Formats) > 0 {
+			req.setFormat(info.Formats[pickFormat(info.Formats, e.Height)])
+		} else {
+			a.recordHistory(dl, downloadRecord{URL: e.URL, Title: info.Title, LangCode: e.LangCode, StartedAt: startedAt, Err: errNoFormats, RetryOf: e.ID})
+			a.showError(i18n.Sprintf("Erro ao buscar vídeo: %v", errNoFormats))
+			return
+		}
+	}
+
+	a.downloadOrQueue(dl, req)
+}
+
+// retryFailedToday repete em sequência as falhas de hoje que ainda não foram
+// resolvidas por um download bem-sucedido posterior.
+func (a *App) retryFailedToday() {
+	entries, err := a.history.List()
+	if err != nil {
+		a.showError(err.Error())
+		return
+	}
+
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	failed := history.PendingFailures(entries, today)
+	if len(failed) == 0 {
+		a.showError(i18n.T("Nenhuma falha pendente hoje."))
+		return
+	}
+
+	a.clearScreen()
+	i18n.Printf(" %d download(s) com falha hoje:\n", len(failed))
+	for _, e := range failed {
+		fmt.Printf("  - %s\n", formatHistoryLine(e))
+	}
+	fmt.Println()
+	i18n.Println(" Qualidade máxima (ex: 720). ENTER mantém a qualidade original, 0 volta.")
+	a.printSeparator()
+
+	input := a.readInput()
+	if input == "0" {
+		return
+	}
+	override := 0
+	if input != "" {
+		if _, err := fmt.Sscanf(strings.TrimSuffix(strings.ToLower(input), "p"), "%d", &override); err != nil || override < 0 {
+			a.showError(i18n.T("Qualidade inválida!"))
+			return
+		}
+	}
+
+	if err := a.cfg.EnsureDownloadDir(); err != nil {
+		a.showError(i18n.Sprintf("Erro ao criar pasta de download: %v", err))
+		return
+	}
+
+	a.clearScreen()
+	results := make([]autoResult, 0, len(failed))
+	for i, e := range failed {
+		policy := downloadPolicy{MaxHeight: e.Height, Lang: e.LangCode, Audio: entryAudio(e), Subtitles: entrySubtitles(e), Clip: entryClip(e), retryOf: e.ID}
+		policy.Chapters, _ = downloader.ParseChapterMode(e.ChapterMode)
+		policy.Items = formatPositions(e.Items)
+		policy.ExtraLangs = e.ExtraLangs
+		policy.WhatsAppCopy = e.WhatsAppCopy
+		if override > 0 {
+			policy.MaxHeight = override
+		}
+
+		fmt.Printf("\n [%d/%d] %s\n", i+1, len(failed), e.URL)
+		res := a.autoDownload(e.URL, policy, os.Stdout)
+		if res.Err != nil {
+			i18n.Printf(" [ERRO] %v\n", res.Err)
+		}
+		results = append(results, res)
+	}
+
+	a.clearScreen()
+	printBatchSummary(os.Stdout, results)
+	a.printFooter()
+	i18n.Print("\n Pressione ENTER para continuar...")
+	a.reader.ReadString('\n')
+}
+
+// entryAudio reconstrói as opções do modo somente áudio de uma entrada, ou nil
+// quando o download foi de vídeo.
+func entryAudio(e history.Entry) *downloader.AudioOptions {
+	format, ok := downloader.ParseAudioFormat(e.AudioFormat)
+	if !ok {
+		return nil
+	}
+	return &downloader.AudioOptions{Format: format, Bitrate: e.AudioBitrate}
+}
+
+// entryClip reconstrói o trecho pedido em uma entrada, ou nil para o vídeo inteiro.
+func entryClip(e history.Entry) *downloader.ClipRange {
+	if e.ClipStart <= 0 && e.ClipEnd <= 0 {
+		return nil
+	}
+	return &downloader.ClipRange{Start: e.ClipStart, End: e.ClipEnd}
+}
+
+// entrySubtitles reconstrói as legendas pedidas em uma entrada, ou nil.
+func entrySubtitles(e history.Entry) *downloader.SubtitleOptions {
+	mode, ok := downloader.ParseSubtitleMode(e.SubtitleMode)
+	if !ok || len(e.SubtitleLangs) == 0 {
+		return nil
+	}
+	return &downloader.SubtitleOptions{Langs: e.SubtitleLangs, Auto: e.SubtitleAuto, Mode: mode}
+}
+
+func formatHistoryLine(e history.Entry) string {
+	status := "OK"
+	switch {
+	case !e.Success:
+		status = i18n.T("FALHA")
+	case len(e.Warnings) > 0:
+		status = i18n.T("AVISO")
+	}
+
+	name := e.Title
+	if name == "" {
+		name = e.URL
+	}
+	return fmt.Sprintf("%s [%s] %s  %s", e.StartedAt.Local().Format("02/01 15:04"), status, e.Platform, truncate(name, 50))
+}
+
+// revealFile abre o gerenciador de arquivos na pasta do arquivo (selecionando-o
+// quando o sistema permitir).
+func revealFile(path string) error {
+	if _, err := os.Stat(path); err != nil {
+		return i18n.Errorf("arquivo não encontrado: %s", path)
+	}
+
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "windows":
+		cmd = exec.Command("explorer", "/select,", path)
+	case "darwin":
+		cmd = exec.Command("open", "-R", path)
+	default:
+		cmd = exec.Command("xdg-open", filepath.Dir(path))
+	}
+	return cmd.Start()
+}
@@ -0,0 +1,70 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/signal"
+
+	"github.com/diogocardoso/DownloaderTube/internal/i18n"
+)
+
+// errCanceled indica que o usuário interrompeu o download com Ctrl+C.
+var errCanceled = i18n.NewError("download cancelado")
+
+// handleInterrupts troca o comportamento padrão do Ctrl+C: com um download em
+// primeiro plano, apenas ele é cancelado (e seus arquivos parciais removidos) e
+// o programa continua; sem download em primeiro plano, os jobs da fila são
+// cancelados e o programa encerra.
+func (a *App) handleInterrupts() {
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt)
+
+	go func() {
+		for range sig {
+			a.fgMu.Lock()
+			cancel := a.fgCancel
+			a.fgCancel = nil
+			a.fgMu.Unlock()
+
+			if cancel != nil {
+				cancel()
+				continue
+			}
+			a.shutdown()
+			os.Exit(ExitCanceled)
+		}
+	}()
+}
+
+// foregroundContext cria o contexto do download em primeiro plano, cancelado
+// pelo Ctrl+C. A função retornada deve ser chamada ao fim do download.
+func (a *App) foregroundContext() (context.Context, func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	a.fgMu.Lock()
+	a.fgCancel = cancel
+	a.fgMu.Unlock()
+
+	return ctx, func() {
+		a.fgMu.Lock()
+		a.fgCancel = nil
+		a.fgMu.Unlock()
+		cancel()
+	}
+}
+
+// shutdown cancela os downloads da fila e espera a limpeza dos arquivos parciais.
+func (a *App) shutdown() {
+	if a.queue == nil {
+		return
+	}
+	if n := a.queue.CancelAll(); n > 0 {
+		i18n.Printf("\n Cancelando %d download(s) da fila...\n", n)
+	}
+	a.queue.Close()
+}
+
+func isCanceled(err error) bool {
+	return errors.Is(err, errCanceled) || errors.Is(err, context.Canceled)
+}
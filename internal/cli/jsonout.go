@@ -0,0 +1,168 @@
+package cli
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+
+	"github.com/diogocardoso/DownloaderTube/internal/downloader"
+	"github.com/diogocardoso/DownloaderTube/internal/i18n"
+	"github.com/diogocardoso/DownloaderTube/internal/subscriptions"
+)
+
+// jsonWriter implementa a saída --json: cada evento é um objeto JSON em uma
+// linha (JSON Lines), identificado pelo campo "type":
+//
+//	info      metadados do vídeo (formatos e idiomas), antes do download
+//	progress  um ProgressEvent do download
+//	result    resultado final de uma URL, com DownloadResult, FileProbeInfo e avisos
+//	summary   totais do lote (apenas no comando batch)
+//	sync      resultado de uma assinatura no comando sync, com os itens baixados
+type jsonWriter struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+func newJSONWriter(w io.Writer) *jsonWriter {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	return &jsonWriter{enc: enc}
+}
+
+type jsonInfoEvent struct {
+	Type     string `json:"type"`
+	URL      string `json:"url"`
+	Platform string `json:"platform"`
+	*downloader.VideoInfo
+}
+
+type jsonProgressEvent struct {
+	Type string `json:"type"`
+	URL  string `json:"url"`
+	downloader.ProgressEvent
+	ETASeconds float64 `json:"eta_seconds,omitempty"`
+}
+
+type jsonResultEvent struct {
+	Type     string                   `json:"type"`
+	URL      string                   `json:"url"`
+	Platform string                   `json:"platform,omitempty"`
+	Title    string                   `json:"title,omitempty"`
+	Format   *downloader.Format       `json:"format,omitempty"`
+	Audio    *downloader.AudioOptions `json:"audio,omitempty"`
+	Clip     *downloader.ClipRange    `json:"clip,omitempty"`
+	LangCode string                   `json:"lang_code,omitempty"`
+	// ExtraLangs são as faixas de áudio mantidas além de LangCode.
+	ExtraLangs []string                   `json:"extra_langs,omitempty"`
+	Success    bool                       `json:"success"`
+	Error      string                     `json:"error,omitempty"`
+	ExitCode   int                        `json:"exit_code"`
+	Result     *downloader.DownloadResult `json:"result,omitempty"`
+	Probe      *downloader.FileProbeInfo  `json:"probe,omitempty"`
+	Warnings   []string                   `json:"warnings"`
+}
+
+type jsonSyncEvent struct {
+	Type         string                      `json:"type"`
+	Subscription subscriptions.Source        `json:"subscription"`
+	Success      bool                        `json:"success"`
+	Error        string                      `json:"error,omitempty"`
+	ExitCode     int                         `json:"exit_code"`
+	Items        []downloader.DownloadResult `json:"items"`
+}
+
+type jsonSummaryEvent struct {
+	Type      string `json:"type"`
+	Total     int    `json:"total"`
+	Succeeded int    `json:"succeeded"`
+	Warned    int    `json:"warned"`
+	Failed    int    `json:"failed"`
+}
+
+func (j *jsonWriter) write(v any) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.enc.Encode(v)
+}
+
+func (j *jsonWriter) info(url, platform string, info *downloader.VideoInfo) {
+	j.write(jsonInfoEvent{Type: "info", URL: url, Platform: platform, VideoInfo: info})
+}
+
+// progress retorna um callback que emite cada evento de progresso como uma linha JSON.
+func (j *jsonWriter) progress(url string) downloader.ProgressFunc {
+	return func(ev downloader.ProgressEvent) {
+		j.write(jsonProgressEvent{Type: "progress", URL: url, ProgressEvent: ev, ETASeconds: ev.ETA.Seconds()})
+	}
+}
+
+// result emite o resultado final da URL. Em caso de sucesso, inspeciona o
+// arquivo com ffprobe; se isso falhar, o erro vira um aviso.
+func (j *jsonWriter) result(res autoResult) {
+	ev := jsonResultEvent{
+		Type:       "result",
+		URL:        res.URL,
+		Platform:   string(res.Platform),
+		Title:      res.Title,
+		LangCode:   res.LangCode,
+		ExtraLangs: res.ExtraLangs,
+		Clip:       res.Clip,
+		Success:    res.Err == nil,
+		ExitCode:   ExitOK,
+		Warnings:   append([]string{}, res.Warnings...),
+	}
+	if res.Audio != nil {
+		ev.Audio = res.Audio
+	} else if res.Label != "" {
+		ev.Format = &downloader.Format{Height: res.Height, Label: res.Label}
+	}
+	if res.Err != nil {
+		ev.Error = res.Err.Error()
+		ev.ExitCode = exitCodeFor(res.Err)
+	} else {
+		result := res.Result
+		ev.Result = &result
+		if result.FilePath != "" {
+			probe, err := downloader.ProbeFile(result.FilePath)
+			if err != nil {
+				ev.Warnings = append(ev.Warnings, i18n.Sprintf("Não foi possível analisar o arquivo: %v", err))
+			} else {
+				ev.Probe = probe
+			}
+		}
+	}
+	j.write(ev)
+}
+
+func (j *jsonWriter) summary(results []autoResult) {
+	ev := jsonSummaryEvent{Type: "summary", Total: len(results)}
+	for _, r := range results {
+		switch {
+		case r.Err != nil:
+			ev.Failed++
+		case len(r.Warnings) > 0:
+			ev.Warned++
+		default:
+			ev.Succeeded++
+		}
+	}
+	j.write(ev)
+}
+
+func (j *jsonWriter) sync(res syncResult) {
+	ev := jsonSyncEvent{
+		Type:         "sync",
+		Subscription: res.Source,
+		Success:      res.Err == nil,
+		ExitCode:     ExitOK,
+		Items:        res.Items,
+	}
+	if ev.Items == nil {
+		ev.Items = []downloader.DownloadResult{}
+	}
+	if res.Err != nil {
+		ev.Error = res.Err.Error()
+		ev.ExitCode = exitCodeFor(res.Err)
+	}
+	j.write(ev)
+}
@@ -0,0 +1,62 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/diogocardoso/DownloaderTube/internal/downloader"
+	"github.com/diogocardoso/DownloaderTube/pkg/validator"
+)
+
+func TestJSONWriterEmitsOneObjectPerLine(t *testing.T) {
+	var buf bytes.Buffer
+	j := newJSONWriter(&buf)
+
+	j.info("https://youtu.be/x", "youtube", &downloader.VideoInfo{
+		Title:   "Vídeo <teste>",
+		Formats: []downloader.Format{{Height: 720, Label: "720p"}},
+	})
+	j.progress("https://youtu.be/x")(downloader.ProgressEvent{
+		Phase: downloader.PhaseDownloadingVideo, Downloaded: 50, Total: 100, Percent: 50, ETA: 90 * time.Second,
+	})
+	j.result(autoResult{
+		URL:      "https://youtu.be/x",
+		Platform: validator.PlatformYouTube,
+		Label:    "720p",
+		Height:   720,
+		Err:      fmt.Errorf("%w: timeout", errVideoInfo),
+	})
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("esperava 3 linhas, veio %d: %q", len(lines), buf.String())
+	}
+
+	var info map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &info); err != nil {
+		t.Fatal(err)
+	}
+	if info["type"] != "info" || info["title"] != "Vídeo <teste>" || len(info["formats"].([]any)) != 1 {
+		t.Errorf("evento info inesperado: %s", lines[0])
+	}
+
+	var progress map[string]any
+	if err := json.Unmarshal([]byte(lines[1]), &progress); err != nil {
+		t.Fatal(err)
+	}
+	if progress["type"] != "progress" || progress["phase"] != "downloading_video" || progress["eta_seconds"] != 90.0 {
+		t.Errorf("evento progress inesperado: %s", lines[1])
+	}
+
+	var result jsonResultEvent
+	if err := json.Unmarshal([]byte(lines[2]), &result); err != nil {
+		t.Fatal(err)
+	}
+	if result.Type != "result" || result.Success || result.ExitCode != ExitInfoFailed || result.Result != nil || result.Format.Height != 720 {
+		t.Errorf("evento result inesperado: %s", lines[2])
+	}
+}
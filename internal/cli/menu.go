@@ -2,14 +2,26 @@ package cli
 
 import (
 	"bufio"
+	"context"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"runtime"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
 
 	"github.com/diogocardoso/DownloaderTube/internal/config"
 	"github.com/diogocardoso/DownloaderTube/internal/downloader"
+	"github.com/diogocardoso/DownloaderTube/internal/history"
+	"github.com/diogocardoso/DownloaderTube/internal/i18n"
+	"github.com/diogocardoso/DownloaderTube/internal/queue"
+	"github.com/diogocardoso/DownloaderTube/internal/subscriptions"
 	"github.com/diogocardoso/DownloaderTube/pkg/validator"
 )
 
@@ -19,11 +31,28 @@ type App struct {
 	ytDownloader downloader.Downloader
 	fbDownloader downloader.Downloader
 	igDownloader downloader.Downloader
+	queue        *queue.Queue
+	history      *history.Store
+	subs         *subscriptions.Store
+
+	// json, quando definido, troca a saída legível do modo não interativo por
+	// eventos JSON (ver jsonWriter).
+	json *jsonWriter
+
+	// fgCancel cancela o download em primeiro plano (ver foregroundContext).
+	fgMu     sync.Mutex
+	fgCancel context.CancelFunc
+
+	// ensureDeps instala o yt-dlp e o ffmpeg quando faltarem (ver
+	// SetDependencyCheck).
+	ensureDeps func() error
 }
 
-func New(cfg *config.Config, ytDL downloader.Downloader, fbDL downloader.Downloader, igDL downloader.Downloader) *App {
+func New(cfg *config.Config, hist *history.Store, subs *subscriptions.Store, ytDL downloader.Downloader, fbDL downloader.Downloader, igDL downloader.Downloader) *App {
 	return &App{
 		cfg:          cfg,
+		history:      hist,
+		subs:         subs,
 		reader:       bufio.NewReader(os.Stdin),
 		ytDownloader: ytDL,
 		fbDownloader: fbDL,
@@ -31,15 +60,51 @@ func New(cfg *config.Config, ytDL downloader.Downloader, fbDL downloader.Downloa
 	}
 }
 
+// SetDependencyCheck define a função que garante o yt-dlp e o ffmpeg. Ela só
+// roda antes de baixar: no menu, ao abrir; nos comandos, depois de validar as
+// opções, para que "help" e erros de uso não instalem nada.
+func (a *App) SetDependencyCheck(ensure func() error) {
+	a.ensureDeps = ensure
+}
+
+// ensureDependencies roda a verificação das dependências, mostrando o erro em
+// stderr. Retorna false quando elas não puderam ser instaladas.
+func (a *App) ensureDependencies() bool {
+	if a.ensureDeps == nil {
+		return true
+	}
+	if err := a.ensureDeps(); err != nil {
+		i18n.Fprintf(os.Stderr, "Erro: %v\n", err)
+		return false
+	}
+	return true
+}
+
 func (a *App) Run() {
+	if !a.ensureDependencies() {
+		os.Exit(1)
+	}
+	// Os comandos não interativos criam a pasta só depois de aplicar o --out.
+	if err := a.cfg.EnsureDownloadDir(); err != nil {
+		i18n.Fprintf(os.Stderr, "Erro ao criar diretório de download: %v\n", err)
+		os.Exit(1)
+	}
+	a.handleInterrupts()
 	for {
 		a.clearScreen()
 		a.printHeader()
 		fmt.Println(" 1 - Youtube")
 		fmt.Println(" 2 - Facebook")
 		fmt.Println(" 3 - Instagram")
+		i18n.Println(" 4 - Download em lote")
+		i18n.Println(" 5 - Fila de downloads")
+		i18n.Println(" 6 - Downloads recentes")
+		i18n.Println(" 7 - Configurações")
+		i18n.Println(" 8 - Assinaturas (canais e perfis)")
 		fmt.Println()
-		fmt.Println(" x - Sair")
+		i18n.Println(" Ou cole o link do vídeo (plataforma detectada automaticamente)")
+		fmt.Println()
+		i18n.Println(" x - Sair")
 		a.printFooter()
 
 		choice := a.readInput()
@@ -51,11 +116,112 @@ func (a *App) Run() {
 			a.facebookMenu()
 		case "3":
 			a.instagramMenu()
+		case "4":
+			a.batchMenu()
+		case "5":
+			a.queueMenu()
+		case "6":
+			a.historyMenu()
+		case "7":
+			a.settingsMenu()
+		case "8":
+			a.subscriptionsMenu()
 		case "x":
-			fmt.Println("\n Até logo!")
+			if !a.confirmExit() {
+				continue
+			}
+			a.shutdown()
+			i18n.Println("\n Até logo!")
 			return
 		default:
-			a.showError("Opção inválida!")
+			if validator.LooksLikeURL(choice) {
+				a.processPastedURL(choice)
+				continue
+			}
+			a.showError(i18n.T("Opção inválida!"))
+		}
+	}
+}
+
+// processPastedURL classifica o link colado no menu principal e despacha para o
+// Downloader correto, perguntando a plataforma apenas quando houver dúvida.
+func (a *App) processPastedURL(raw string) {
+	url, dl, ok := a.resolvePastedURL(raw)
+	if !ok {
+		return
+	}
+	a.processVideo(url, dl)
+}
+
+// resolvePastedURL identifica a plataforma do link e retorna a URL normalizada
+// com o Downloader correspondente.
+func (a *App) resolvePastedURL(raw string) (string, downloader.Downloader, bool) {
+	matches := validator.Classify(raw)
+
+	var match validator.Match
+	if len(matches) == 1 {
+		match = matches[0]
+	} else {
+		var ok bool
+		match, ok = a.askPlatform(raw, matches)
+		if !ok {
+			return "", nil, false
+		}
+	}
+
+	dl := a.downloaderFor(match.Platform)
+	if dl == nil {
+		a.showError(i18n.T("Plataforma não suportada."))
+		return "", nil, false
+	}
+	return match.URL, dl, true
+}
+
+// askPlatform pede ao usuário para escolher a plataforma quando o link é ambíguo
+// (mais de um candidato) ou não reconhecido (nenhum candidato).
+func (a *App) askPlatform(raw string, matches []validator.Match) (validator.Match, bool) {
+	options := matches
+	if len(options) == 0 {
+		url := validator.NormalizeURL(raw)
+		for _, p := range validator.Platforms {
+			options = append(options, validator.Match{Platform: p, URL: url})
+		}
+	}
+
+	for {
+		a.clearScreen()
+		if len(matches) == 0 {
+			i18n.Println(" Não foi possível identificar a plataforma deste link.")
+			i18n.Println(" Escolha qual usar para tentar o download:")
+		} else {
+			i18n.Println(" Este link pode ser de mais de uma plataforma.")
+			i18n.Println(" Escolha qual usar:")
+		}
+		fmt.Println()
+		for i, m := range options {
+			fmt.Printf(" %d - %s (%s)\n", i+1, m.Platform.Label(), m.URL)
+		}
+		fmt.Println()
+		i18n.Println(" 0 - Voltar")
+		i18n.Println(" x - Sair")
+		a.printSeparator()
+
+		choice := a.readInput()
+
+		switch strings.ToLower(choice) {
+		case "0":
+			return validator.Match{}, false
+		case "x":
+			a.shutdown()
+			i18n.Println("\n Até logo!")
+			os.Exit(0)
+		default:
+			idx := a.parseChoice(choice, len(options))
+			if idx < 0 {
+				a.showError(i18n.T("Opção inválida!"))
+				continue
+			}
+			return options[idx], true
 		}
 	}
 }
@@ -63,10 +229,10 @@ func (a *App) Run() {
 func (a *App) youtubeMenu() {
 	for {
 		a.clearScreen()
-		fmt.Println(" Youtube url:")
+		i18n.Println(" Youtube url:")
 		fmt.Println()
-		fmt.Println(" 0 - Voltar")
-		fmt.Println(" x - Sair")
+		i18n.Println(" 0 - Voltar")
+		i18n.Println(" x - Sair")
 		a.printSeparator()
 
 		input := a.readInput()
@@ -75,11 +241,12 @@ func (a *App) youtubeMenu() {
 		case "0":
 			return
 		case "x":
-			fmt.Println("\n Até logo!")
+			a.shutdown()
+			i18n.Println("\n Até logo!")
 			os.Exit(0)
 		default:
 			if !validator.IsYouTubeURL(input) {
-				a.showError("URL inválida! Informe uma URL válida do YouTube.")
+				a.showError(i18n.T("URL inválida! Informe uma URL válida do YouTube."))
 				continue
 			}
 			a.processVideo(input, a.ytDownloader)
@@ -90,10 +257,10 @@ func (a *App) youtubeMenu() {
 func (a *App) facebookMenu() {
 	for {
 		a.clearScreen()
-		fmt.Println(" Facebook url:")
+		i18n.Println(" Facebook url:")
 		fmt.Println()
-		fmt.Println(" 0 - Voltar")
-		fmt.Println(" x - Sair")
+		i18n.Println(" 0 - Voltar")
+		i18n.Println(" x - Sair")
 		a.printSeparator()
 
 		input := a.readInput()
@@ -102,11 +269,12 @@ func (a *App) facebookMenu() {
 		case "0":
 			return
 		case "x":
-			fmt.Println("\n Até logo!")
+			a.shutdown()
+			i18n.Println("\n Até logo!")
 			os.Exit(0)
 		default:
 			if !validator.IsFacebookURL(input) {
-				a.showError("URL inválida! Informe uma URL válida do Facebook.")
+				a.showError(i18n.T("URL inválida! Informe uma URL válida do Facebook."))
 				continue
 			}
 			a.processVideo(input, a.fbDownloader)
@@ -117,10 +285,10 @@ func (a *App) facebookMenu() {
 func (a *App) instagramMenu() {
 	for {
 		a.clearScreen()
-		fmt.Println(" Instagram url:")
+		i18n.Println(" Instagram url:")
 		fmt.Println()
-		fmt.Println(" 0 - Voltar")
-		fmt.Println(" x - Sair")
+		i18n.Println(" 0 - Voltar")
+		i18n.Println(" x - Sair")
 		a.printSeparator()
 
 		input := a.readInput()
@@ -129,11 +297,12 @@ func (a *App) instagramMenu() {
 		case "0":
 			return
 		case "x":
-			fmt.Println("\n Até logo!")
+			a.shutdown()
+			i18n.Println("\n Até logo!")
 			os.Exit(0)
 		default:
 			if !validator.IsInstagramURL(input) {
-				a.showError("URL inválida! Informe uma URL válida do Instagram.")
+				a.showError(i18n.T("URL inválida! Informe uma URL válida do Instagram."))
 				continue
 			}
 			a.processVideo(input, a.igDownloader)
@@ -142,211 +311,781 @@ func (a *App) instagramMenu() {
 }
 
 func (a *App) processVideo(url string, dl downloader.Downloader) {
+	if lister, ok := dl.(downloader.PlaylistLister); ok && validator.IsYouTubePlaylistURL(url) {
+		a.processPlaylist(url, lister)
+		return
+	}
+	if collector, ok := dl.(downloader.CollectionDownloader); ok && validator.IsInstagramCollectionURL(url) {
+		a.processCollection(url, dl, collector)
+		return
+	}
+	req, ok := a.prepareVideo(url, dl)
+	if !ok {
+		return
+	}
+	a.downloadOrQueue(dl, req)
+}
+
+// prepareVideo busca as informações do vídeo e conduz as telas de idioma e
+// qualidade. Retorna ok=false quando o usuário volta ou ocorre um erro.
+func (a *App) prepareVideo(url string, dl downloader.Downloader) (downloadRequest, bool) {
 	a.clearScreen()
-	fmt.Println(" Buscando informações do vídeo...")
+	i18n.Println(" Buscando informações do vídeo...")
 	a.printSeparator()
 
-	info, err := dl.GetVideoInfo(url)
+	ctx, done := a.foregroundContext()
+	info, err := dl.GetVideoInfo(ctx, url)
+	done()
 	if err != nil {
-		a.showError(fmt.Sprintf("Erro ao buscar vídeo: %v", err))
-		return
+		a.showError(i18n.Sprintf("Erro ao buscar vídeo: %v", err))
+		return downloadRequest{}, false
 	}
 
-	if len(info.Formats) == 0 {
-		a.showError("Nenhum formato de vídeo disponível.")
-		return
+	// Sem formatos de vídeo ainda é possível baixar só o áudio ou as fotos de
+	// um carrossel.
+	if len(info.Formats) == 0 && len(info.AudioTracks) == 0 && !carouselPhotosOnly(info.Items, nil) {
+		a.showError(i18n.T("Nenhum formato de vídeo disponível."))
+		return downloadRequest{}, false
 	}
 
-	langCode := ""
-	if len(info.Languages) > 1 {
+	req := downloadRequest{Title: info.Title}
+	req.URL = url
+	if len(info.Items) > 1 {
 		var ok bool
-		langCode, ok = a.selectLanguage(info)
-		if !ok {
-			return
+		if req.Items, ok = a.selectCarouselItems(info); !ok {
+			return downloadRequest{}, false
+		}
+	}
+	if carouselPhotosOnly(info.Items, req.Items) {
+		req.Label = i18n.T("fotos")
+		return req, true
+	}
+	if len(info.Languages) > 1 {
+		if !a.selectLanguage(info, &req, supportsMultiAudio(dl)) {
+			return downloadRequest{}, false
 		}
 	} else if len(info.Languages) == 1 {
-		langCode = info.Languages[0].Code
+		req.LangCode = info.Languages[0].Code
+	}
+
+	// Com o preset padrão da plataforma atendido, a tela de qualidade é opcional.
+	platform := a.platformOf(dl)
+	chosen := false
+	if preset := a.cfg.QualityPreset(string(platform)); preset != "" && a.cfg.SkipQualityScreen {
+		if idx, ok := preset.Resolve(info.Formats); ok {
+			req.setFormat(info.Formats[idx])
+			chosen = true
+		}
+	}
+	if !chosen && !a.selectQuality(info, &req, platform) {
+		return downloadRequest{}, false
+	}
+
+	if req.Audio == nil && len(info.Subtitles) > 0 && !a.selectSubtitles(info, &req) {
+		return downloadRequest{}, false
+	}
+	if req.Audio == nil && req.Clip == nil && len(info.Chapters) > 1 && !a.selectChapters(info, &req) {
+		return downloadRequest{}, false
+	}
+	return req, true
+}
+
+// selectChapters mostra os capítulos do vídeo e pergunta se cada um deve virar
+// um arquivo próprio. ENTER baixa só o vídeo inteiro.
+func (a *App) selectChapters(info *downloader.VideoInfo, req *downloadRequest) bool {
+	for {
+		a.clearScreen()
+		i18n.Printf(" Vídeo: %s\n", info.Title)
+		fmt.Println()
+		i18n.Printf(" Capítulos (%d):\n", len(info.Chapters))
+		for i, c := range info.Chapters {
+			fmt.Printf(" %2d. %s  %s\n", i+1, downloader.FormatTimestamp(c.Start), c.Title)
+		}
+		fmt.Println()
+		i18n.Println(" ENTER - Vídeo inteiro")
+		i18n.Println(" 1 - Um arquivo por capítulo")
+		i18n.Println(" 2 - Um arquivo por capítulo + vídeo inteiro")
+		fmt.Println()
+		i18n.Println(" 0 - Voltar")
+		a.printSeparator()
+
+		switch a.readInput() {
+		case "":
+			req.Chapters = ""
+		case "1":
+			req.Chapters = downloader.ChaptersSplit
+		case "2":
+			req.Chapters = downloader.ChaptersBoth
+		case "0":
+			return false
+		default:
+			a.showError(i18n.T("Opção inválida!"))
+			continue
+		}
+		return true
+	}
+}
+
+// chapterModeLabel descreve o modo de capítulos para exibição.
+func chapterModeLabel(mode downloader.ChapterMode) string {
+	if mode == downloader.ChaptersBoth {
+		return i18n.T("um arquivo por capítulo + vídeo inteiro")
 	}
+	return i18n.T("um arquivo por capítulo")
+}
+
+// selectSubtitles pergunta quais legendas baixar (várias, ver parseSelection)
+// e se devem ser embutidas no MP4 ou salvas em .srt. ENTER segue sem
+// legendas.
+func (a *App) selectSubtitles(info *downloader.VideoInfo, req *downloadRequest) bool {
+	for {
+		a.clearScreen()
+		i18n.Printf(" Vídeo: %s\n", info.Title)
+		fmt.Println()
+		i18n.Println(" Legendas disponíveis:")
+		for i, t := range info.Subtitles {
+			fmt.Printf(" %d - %s\n", i+1, t.Label())
+		}
+		fmt.Println()
+		i18n.Println(" Escolha uma ou mais (ex: 1,3 ou 1-3; all = todas).")
+		i18n.Println(" ENTER - sem legendas")
+		fmt.Println()
+		i18n.Println(" 0 - Voltar")
+		a.printSeparator()
 
-	a.selectQualityAndDownload(url, info, langCode, dl)
+		choice := a.readInput()
+		switch choice {
+		case "":
+			req.Subtitles = nil
+			return true
+		case "0":
+			return false
+		}
+
+		picks, err := parseSelection(choice, len(info.Subtitles))
+		if err != nil {
+			a.showError(err.Error())
+			continue
+		}
+		var opts downloader.SubtitleOptions
+		for _, idx := range picks {
+			t := info.Subtitles[idx]
+			opts.Langs = append(opts.Langs, t.Lang)
+			opts.Auto = opts.Auto || t.Auto
+		}
+
+		fmt.Println()
+		i18n.Println(" 1 - Embutir no MP4 (selecionável no player)")
+		i18n.Println(" 2 - Salvar arquivos .srt ao lado do vídeo")
+		a.printSeparator()
+
+		switch a.readInput() {
+		case "1":
+			opts.Mode = downloader.SubtitleEmbed
+		case "2":
+			opts.Mode = downloader.SubtitleSRT
+		default:
+			a.showError(i18n.T("Opção inválida!"))
+			continue
+		}
+		req.Subtitles = &opts
+		return true
+	}
 }
 
-func (a *App) selectLanguage(info *downloader.VideoInfo) (string, bool) {
+// selectLanguage pergunta o idioma do áudio e o grava em req. Com mais de um
+// idioma escolhido, cada um vira uma faixa no mesmo arquivo: pergunta qual é
+// a faixa padrão e se deve ser salva também a cópia para WhatsApp só com ela.
+func (a *App) selectLanguage(info *downloader.VideoInfo, req *downloadRequest, multi bool) bool {
 	for {
 		a.clearScreen()
-		fmt.Printf(" Vídeo: %s\n", info.Title)
-		fmt.Printf(" Duração: %s\n", info.Duration)
+		i18n.Printf(" Vídeo: %s\n", info.Title)
+		i18n.Printf(" Duração: %s\n", info.Duration)
 		fmt.Println()
-		fmt.Println(" Idiomas disponíveis:")
+		i18n.Println(" Idiomas disponíveis:")
 
 		for i, lang := range info.Languages {
 			fmt.Printf(" %d - %s (%s)\n", i+1, lang.Name, lang.Code)
 		}
 
 		fmt.Println()
-		fmt.Println(" 0 - Voltar")
-		fmt.Println(" x - Sair")
+		if multi {
+			i18n.Println(" Escolha um ou mais (ex: 1 ou 1,3); vários viram faixas no mesmo arquivo.")
+			fmt.Println()
+		}
+		i18n.Println(" 0 - Voltar")
+		i18n.Println(" x - Sair")
 		a.printSeparator()
 
 		choice := a.readInput()
 
 		switch strings.ToLower(choice) {
 		case "0":
-			return "", false
+			return false
 		case "x":
-			fmt.Println("\n Até logo!")
+			a.shutdown()
+			i18n.Println("\n Até logo!")
 			os.Exit(0)
-		default:
-			idx := a.parseChoice(choice, len(info.Languages))
-			if idx < 0 {
-				a.showError("Opção inválida!")
+		}
+
+		picks, err := parseSelection(choice, len(info.Languages))
+		if err != nil {
+			a.showError(err.Error())
+			continue
+		}
+		if !multi && len(picks) > 1 {
+			a.showError(i18n.T("Esta plataforma mantém só uma faixa de áudio; escolha um idioma."))
+			continue
+		}
+		langs := make([]downloader.AudioLang, len(picks))
+		for i, idx := range picks {
+			langs[i] = info.Languages[idx]
+		}
+		req.LangCode, req.ExtraLangs, req.WhatsAppCopy = langs[0].Code, nil, false
+		if len(langs) == 1 {
+			return true
+		}
+
+		fmt.Println()
+		i18n.Println(" Faixa de áudio padrão:")
+		for i, lang := range langs {
+			fmt.Printf(" %d - %s (%s)\n", i+1, lang.Name, lang.Code)
+		}
+		i18n.Println(" ENTER - a primeira")
+		a.printSeparator()
+
+		def := 0
+		if input := a.readInput(); input != "" {
+			if def = a.parseChoice(input, len(langs)); def < 0 {
+				a.showError(i18n.T("Opção inválida!"))
 				continue
 			}
-			return info.Languages[idx].Code, true
 		}
+		req.LangCode = langs[def].Code
+		for i, lang := range langs {
+			if i != def {
+				req.ExtraLangs = append(req.ExtraLangs, lang.Code)
+			}
+		}
+
+		i18n.Printf("\n Salvar também uma cópia para WhatsApp só com %s? (s/N)", langs[def].Name)
+		req.WhatsAppCopy = isYes(a.readInput())
+		return true
 	}
 }
 
-func (a *App) selectQualityAndDownload(url string, info *downloader.VideoInfo, langCode string, dl downloader.Downloader) {
+// selectQuality mostra os formatos do vídeo, os presets de qualidade e o modo
+// somente áudio, e grava a escolha em req. O preset escolhido passa a ser o
+// padrão da plataforma; ENTER usa o padrão atual.
+func (a *App) selectQuality(info *downloader.VideoInfo, req *downloadRequest, platform validator.Platform) bool {
 	for {
+		defaultPreset := a.cfg.QualityPreset(string(platform))
+
 		a.clearScreen()
-		fmt.Printf(" Vídeo: %s\n", info.Title)
-		fmt.Printf(" Duração: %s\n", info.Duration)
-		if langCode != "" {
-			fmt.Printf(" Idioma: %s\n", langCode)
+		i18n.Printf(" Vídeo: %s\n", info.Title)
+		i18n.Printf(" Duração: %s\n", info.Duration)
+		if req.LangCode != "" {
+			i18n.Printf(" Idioma: %s\n", langsLabel(req.LangCode, req.ExtraLangs))
+		}
+		if req.Clip != nil {
+			i18n.Printf(" Trecho: %s\n", req.Clip.Label())
 		}
 		fmt.Println()
-		fmt.Println(" Qualidades disponíveis:")
+		i18n.Println(" Qualidades disponíveis:")
 
 		for i, f := range info.Formats {
 			fmt.Printf(" %d - %s\n", i+1, f.Label)
 		}
 
 		fmt.Println()
-		fmt.Println(" 0 - Voltar")
-		fmt.Println(" x - Sair")
+		i18n.Println(" Presets:")
+		for i, p := range downloader.QualityPresets {
+			if p == defaultPreset {
+				i18n.Printf(" %c - %s (padrão)\n", 'a'+i, p.Label())
+			} else {
+				fmt.Printf(" %c - %s\n", 'a'+i, p.Label())
+			}
+		}
+		if defaultPreset != "" {
+			i18n.Println(" ENTER - usar o padrão")
+		}
+		fmt.Println()
+		i18n.Println(" m - Somente áudio (MP3, M4A, Opus)")
+		i18n.Println(" t - Baixar só um trecho (início e fim)")
+
+		fmt.Println()
+		i18n.Println(" 0 - Voltar")
+		i18n.Println(" x - Sair")
 		a.printSeparator()
 
-		choice := a.readInput()
+		choice := strings.ToLower(a.readInput())
 
-		switch strings.ToLower(choice) {
-		case "0":
-			return
-		case "x":
-			fmt.Println("\n Até logo!")
+		var preset downloader.QualityPreset
+		switch {
+		case choice == "0":
+			return false
+		case choice == "x":
+			a.shutdown()
+			i18n.Println("\n Até logo!")
 			os.Exit(0)
+		case choice == "t":
+			a.askClip(req)
+			continue
+		case choice == "m":
+			if opts, ok := a.selectAudio(info); ok {
+				req.setAudio(opts)
+				return true
+			}
+			continue
+		case choice == "" && defaultPreset != "":
+			preset = defaultPreset
+		case len(choice) == 1 && choice[0] >= 'a' && int(choice[0]-'a') < len(downloader.QualityPresets):
+			preset = downloader.QualityPresets[choice[0]-'a']
 		default:
 			idx := a.parseChoice(choice, len(info.Formats))
 			if idx < 0 {
-				a.showError("Opção inválida!")
+				a.showError(i18n.T("Opção inválida!"))
 				continue
 			}
-			a.startDownload(url, info, idx, langCode, dl)
-			return
+			req.setFormat(info.Formats[idx])
+			return true
 		}
+
+		idx, ok := preset.Resolve(info.Formats)
+		if !ok {
+			a.showError(i18n.Sprintf("Nenhuma qualidade deste vídeo atende ao preset %s.", preset.Label()))
+			continue
+		}
+		if preset != defaultPreset {
+			a.rememberPreset(platform, preset)
+		}
+		req.setFormat(info.Formats[idx])
+		return true
 	}
 }
 
-func (a *App) startDownload(url string, info *downloader.VideoInfo, formatIdx int, langCode string, dl downloader.Downloader) {
-	if err := a.cfg.EnsureDownloadDir(); err != nil {
-		a.showError(fmt.Sprintf("Erro ao criar pasta de download: %v", err))
+// askClip pergunta o início e o fim do trecho a baixar. O trecho vale para a
+// qualidade escolhida em seguida, inclusive no modo somente áudio.
+func (a *App) askClip(req *downloadRequest) {
+	i18n.Println("\n Início do trecho (ex: 1:30 ou 1:02:03). ENTER = começo do vídeo, - remove o trecho:")
+	start := a.readInput()
+	if start == "-" {
+		req.Clip = nil
+		return
+	}
+	i18n.Println("\n Fim do trecho (ex: 2:10). ENTER = até o fim do vídeo:")
+	end := a.readInput()
+	if start == "" && end == "" {
 		return
 	}
 
-	selectedFormat := info.Formats[formatIdx]
+	clip, err := downloader.ParseClipRange(start, end)
+	if err != nil {
+		a.showError(err.Error())
+		return
+	}
+	req.Clip = clip
+}
 
-	a.clearScreen()
-	fmt.Printf(" Baixando: %s [%s]\n", info.Title, selectedFormat.Label)
-	fmt.Println()
+// selectAudio pergunta a taxa de bits (quando o vídeo informa as faixas de
+// áudio) e o formato do arquivo no modo somente áudio.
+func (a *App) selectAudio(info *downloader.VideoInfo) (downloader.AudioOptions, bool) {
+	var opts downloader.AudioOptions
 
-	progress := func(current, total int64) {
-		if total <= 0 {
-			// Fallback: algumas saídas do yt-dlp trazem apenas percentual.
-			if current < 0 {
-				current = 0
-			}
-			if current > 100 {
-				current = 100
-			}
-			pct := float64(current)
-			barLen := 30
-			filled := int(pct / 100 * float64(barLen))
-			bar := strings.Repeat("█", filled) + strings.Repeat("░", barLen-filled)
-			fmt.Printf("\r [%s] %.0f%%", bar, pct)
-			return
+	for len(info.AudioTracks) > 0 {
+		a.clearScreen()
+		i18n.Printf(" Vídeo: %s\n", info.Title)
+		fmt.Println()
+		i18n.Println(" Faixas de áudio disponíveis:")
+		for i, t := range info.AudioTracks {
+			fmt.Printf(" %d - %s\n", i+1, t.Label)
+		}
+		i18n.Println(" ENTER - melhor disponível")
+		fmt.Println()
+		i18n.Println(" 0 - Voltar")
+		a.printSeparator()
+
+		choice := a.readInput()
+		if choice == "0" {
+			return opts, false
+		}
+		if choice == "" {
+			break
 		}
-		pct := float64(current) / float64(total) * 100
-		barLen := 30
-		filled := int(pct / 100 * float64(barLen))
+		idx := a.parseChoice(choice, len(info.AudioTracks))
+		if idx < 0 {
+			a.showError(i18n.T("Opção inválida!"))
+			continue
+		}
+		opts.Bitrate = info.AudioTracks[idx].Bitrate
+		break
+	}
+
+	for {
+		a.clearScreen()
+		i18n.Printf(" Vídeo: %s\n", info.Title)
+		fmt.Println()
+		i18n.Println(" Formato do arquivo de áudio:")
+		for i, f := range downloader.AudioFormats {
+			fmt.Printf(" %d - %s\n", i+1, f.Label())
+		}
+		fmt.Println()
+		i18n.Println(" 0 - Voltar")
+		a.printSeparator()
+
+		choice := a.readInput()
+		if choice == "0" {
+			return opts, false
+		}
+		idx := a.parseChoice(choice, len(downloader.AudioFormats))
+		if idx < 0 {
+			a.showError(i18n.T("Opção inválida!"))
+			continue
+		}
+		opts.Format = downloader.AudioFormats[idx]
+		return opts, true
+	}
+}
+
+// rememberPreset grava o preset como padrão da plataforma na configuração.
+func (a *App) rememberPreset(platform validator.Platform, preset downloader.QualityPreset) {
+	if platform == validator.PlatformUnknown {
+		return
+	}
+	s := a.cfg.File()
+	if s.QualityPresets == nil {
+		s.QualityPresets = make(map[string]string)
+	}
+	s.QualityPresets[string(platform)] = string(preset)
+	if err := a.cfg.Save(s); err != nil {
+		a.showError(i18n.Sprintf("Não foi possível salvar o preset padrão: %v", err))
+	}
+}
+
+// downloadRequest descreve um download com qualidade (ou modo somente áudio)
+// e idioma já escolhidos.
+type downloadRequest struct {
+	downloader.DownloadRequest
+	Title string
+	// Label descreve a escolha para exibição (ex: "720p" ou "MP3 128 kbps").
+	Label string
+	// RetryOf é o ID no histórico da tentativa que está sendo repetida.
+	RetryOf string
+}
+
+// langsLabel descreve o idioma do áudio com as faixas extras, quando houver
+// (ex: "en (padrão), pt-BR").
+func langsLabel(code string, extra []string) string {
+	if len(extra) == 0 {
+		return code
+	}
+	return i18n.Sprintf("%s (padrão)", code) + ", " + strings.Join(extra, ", ")
+}
+
+// displayLabel descreve a escolha com o trecho ou os capítulos, quando houver
+// (ex: "720p, 01:30 - 02:10").
+func (r downloadRequest) displayLabel() string {
+	switch {
+	case r.Clip != nil:
+		return r.Label + ", " + r.Clip.Label()
+	case r.Chapters != "" && r.Audio == nil:
+		return r.Label + ", " + chapterModeLabel(r.Chapters)
+	case len(r.Items) > 0:
+		return r.Label + ", " + i18n.Sprintf("itens %s", formatPositions(r.Items))
+	}
+	return r.Label
+}
 
-		bar := strings.Repeat("█", filled) + strings.Repeat("░", barLen-filled)
+func (r *downloadRequest) setFormat(f downloader.Format) {
+	r.Height = f.Height
+	r.Audio = nil
+	r.Label = f.Label
+}
 
-		currentMB := float64(current) / 1024 / 1024
-		totalMB := float64(total) / 1024 / 1024
+func (r *downloadRequest) setAudio(opts downloader.AudioOptions) {
+	r.Height = 0
+	r.Audio = &opts
+	r.Label = opts.Label()
+}
 
-		fmt.Printf("\r [%s] %.0f%% - %.1fMB/%.1fMB", bar, pct, currentMB, totalMB)
+func (a *App) startDownload(dl downloader.Downloader, req downloadRequest) {
+	if err := a.cfg.EnsureDownloadDir(); err != nil {
+		a.showError(i18n.Sprintf("Erro ao criar pasta de download: %v", err))
+		return
 	}
 
-	result, err := dl.Download(url, selectedFormat.Height, langCode, a.cfg.DownloadDir, progress)
+	a.clearScreen()
+	i18n.Printf(" Baixando: %s [%s]\n", req.Title, req.displayLabel())
+	fmt.Println()
+
+	i18n.Println(" Ctrl+C cancela o download.")
 	fmt.Println()
 
+	startedAt := time.Now()
+	ctx, done := a.foregroundContext()
+	dlReq := req.DownloadRequest
+	dlReq.Dest = a.cfg.DownloadDir
+	dlReq.RateLimit = a.cfg.JobRateLimit(1)
+	result, err := dl.Download(ctx, dlReq, newProgressPrinter(os.Stdout))
+	done()
+	fmt.Println()
+
+	histErr := a.recordHistory(dl, downloadRecord{
+		URL:          req.URL,
+		Title:        req.Title,
+		Height:       req.Height,
+		LangCode:     req.LangCode,
+		ExtraLangs:   req.ExtraLangs,
+		WhatsAppCopy: req.WhatsAppCopy,
+		Audio:        req.Audio,
+		Subtitles:    req.Subtitles,
+		Clip:         req.Clip,
+		Chapters:     req.Chapters,
+		Items:        req.Items,
+		StartedAt:    startedAt,
+		Result:       result,
+		Warnings:     resultWarnings(result),
+		Err:          err,
+		RetryOf:      req.RetryOf,
+	})
+
+	if isCanceled(err) {
+		fmt.Println()
+		i18n.Println(" Download cancelado. Arquivos parciais removidos.")
+		i18n.Print(" Pressione ENTER para continuar...")
+		a.reader.ReadString('\n')
+		return
+	}
 	if err != nil {
-		a.showError(fmt.Sprintf("Erro no download: %v", err))
+		a.showError(i18n.Sprintf("Erro no download: %v", err))
 		return
 	}
 
 	fmt.Println()
-	fmt.Println(" Download concluído com sucesso!")
+	i18n.Println(" Download concluído com sucesso!")
 	if result.FilePath != "" {
-		fmt.Printf(" Salvo em: %s\n", result.FilePath)
+		i18n.Printf(" Salvo em: %s\n", result.FilePath)
 	} else {
-		fmt.Printf(" Salvo em: %s\n", a.cfg.DownloadDir)
+		i18n.Printf(" Salvo em: %s\n", a.cfg.DownloadDir)
+	}
+	if result.WhatsAppFile != "" {
+		i18n.Printf(" Cópia para WhatsApp: %s\n", result.WhatsAppFile)
 	}
 
-	if result.FilePath != "" {
-		a.showFileInfo(result.FilePath)
+	photo := downloader.IsPhotoFile(result.FilePath)
+	if result.FilePath != "" && !photo {
+		a.showFileInfo(result.FilePath, req.Audio != nil)
+	}
+
+	if len(result.Files) > 0 {
+		fmt.Println()
+		if req.Chapters != "" {
+			i18n.Printf(" Capítulos (%d):\n", len(result.Files))
+		} else {
+			i18n.Printf(" Arquivos (%d):\n", len(result.Files))
+		}
+		for _, f := range result.Files {
+			fmt.Printf("   %s\n", filepath.Base(f))
+		}
+	}
+	if result.ChapterWarning != "" {
+		i18n.Printf(" [AVISO] Capítulos: %s\n", result.ChapterWarning)
+	}
+	if result.ItemWarning != "" {
+		i18n.Printf(" [AVISO] Carrossel: %s\n", result.ItemWarning)
+	}
+
+	if req.Subtitles != nil {
+		fmt.Println()
+		for _, s := range result.Subtitles {
+			i18n.Printf(" Legenda salva: %s\n", s)
+		}
+		if req.Subtitles.Mode == downloader.SubtitleEmbed && len(result.Subtitles) == 0 && result.SubtitleWarning == "" {
+			i18n.Printf(" Legendas embutidas: %s\n", strings.Join(req.Subtitles.Langs, ", "))
+		}
+		if result.SubtitleWarning != "" {
+			i18n.Printf(" [AVISO] Legendas: %s\n", result.SubtitleWarning)
+		}
 	}
 
 	if result.CompatibilityWarning != "" {
 		fmt.Println()
-		fmt.Printf(" [AVISO] Compatibilidade WhatsApp: %s\n", result.CompatibilityWarning)
-	} else if result.FilePath != "" {
+		i18n.Printf(" [AVISO] Compatibilidade WhatsApp: %s\n", result.CompatibilityWarning)
+	} else if result.FilePath != "" && req.Audio == nil && !photo {
 		fmt.Println()
-		fmt.Println(" Compatibilidade WhatsApp: OK (MP4/H.264/AAC)")
+		i18n.Println(" Compatibilidade WhatsApp: OK (MP4/H.264/AAC)")
+	}
+
+	if histErr != nil {
+		fmt.Println()
+		i18n.Printf(" [AVISO] Não foi possível salvar no histórico: %v\n", histErr)
 	}
 
 	a.printFooter()
-	fmt.Print("\n Pressione ENTER para continuar...")
+	i18n.Print("\n Pressione ENTER para continuar...")
 	a.reader.ReadString('\n')
 }
 
-func (a *App) showFileInfo(filePath string) {
+// newProgressPrinter retorna um callback de progresso que redesenha a linha em w.
+func newProgressPrinter(w io.Writer) downloader.ProgressFunc {
+	lastLen := 0
+	return func(ev downloader.ProgressEvent) {
+		line := formatProgress(ev)
+		// Completa com espaços para apagar restos de uma linha anterior mais longa.
+		n := utf8.RuneCountInString(line)
+		pad := ""
+		if n < lastLen {
+			pad = strings.Repeat(" ", lastLen-n)
+		}
+		lastLen = n
+		fmt.Fprintf(w, "\r%s%s", line, pad)
+	}
+}
+
+// formatProgress descreve o evento em uma linha: barra, tamanho, velocidade,
+// tempo restante e a etapa atual.
+func formatProgress(ev downloader.ProgressEvent) string {
+	label := phaseLabel(ev.Phase)
+	if ev.StreamCount > 1 {
+		label = fmt.Sprintf("%s (%d/%d)", label, ev.StreamIndex, ev.StreamCount)
+	}
+
+	if ev.Percent < 0 {
+		return " " + label + "..."
+	}
+
+	line := progressBar(ev.Percent)
+	if ev.Total > 0 {
+		line += fmt.Sprintf(" - %.1fMB/%.1fMB", float64(ev.Downloaded)/1024/1024, float64(ev.Total)/1024/1024)
+	}
+	if ev.Speed > 0 {
+		if ev.Phase == downloader.PhaseTranscoding || ev.Phase == downloader.PhaseSplitting {
+			line += fmt.Sprintf(" - %.1fx", ev.Speed)
+		} else {
+			line += fmt.Sprintf(" - %.1fMB/s", ev.Speed/1024/1024)
+		}
+	}
+	if ev.RateLimit > 0 && ev.Phase != downloader.PhaseTranscoding && ev.Phase != downloader.PhaseSplitting {
+		line += i18n.Sprintf(" (limite %s)", downloader.FormatRate(ev.RateLimit))
+	}
+	if ev.ETA > 0 {
+		line += " - ETA " + formatETA(ev.ETA)
+	}
+	return line + " - " + label
+}
+
+func phaseLabel(p downloader.Phase) string {
+	switch p {
+	case downloader.PhaseExtracting:
+		return i18n.T("Obtendo informações")
+	case downloader.PhaseDownloadingVideo:
+		return i18n.T("Baixando vídeo")
+	case downloader.PhaseDownloadingAudio:
+		return i18n.T("Baixando áudio")
+	case downloader.PhaseDownloadingPhotos:
+		return i18n.T("Baixando fotos")
+	case downloader.PhaseExtractingAudio:
+		return i18n.T("Extraindo áudio")
+	case downloader.PhaseMerging:
+		return i18n.T("Juntando vídeo e áudio")
+	case downloader.PhaseTranscoding:
+		return i18n.T("Convertendo para MP4 H.264/AAC")
+	case downloader.PhaseSubtitles:
+		return i18n.T("Processando legendas")
+	case downloader.PhaseSplitting:
+		return i18n.T("Separando capítulos")
+	case downloader.PhaseAudioTracks:
+		return i18n.T("Ajustando faixas de áudio")
+	case downloader.PhaseRenaming:
+		return i18n.T("Finalizando arquivo")
+	default:
+		return i18n.T("Baixando")
+	}
+}
+
+func formatETA(d time.Duration) string {
+	d = d.Round(time.Second)
+	h := int(d.Hours())
+	m := int(d.Minutes()) % 60
+	sec := int(d.Seconds()) % 60
+	if h > 0 {
+		return fmt.Sprintf("%d:%02d:%02d", h, m, sec)
+	}
+	return fmt.Sprintf("%02d:%02d", m, sec)
+}
+
+// progressBar desenha a barra para um percentual entre 0 e 100.
+func progressBar(pct float64) string {
+	barLen := 30
+	filled := int(pct / 100 * float64(barLen))
+	if filled < 0 {
+		filled = 0
+	}
+	if filled > barLen {
+		filled = barLen
+	}
+
+	bar := strings.Repeat("█", filled) + strings.Repeat("░", barLen-filled)
+	return fmt.Sprintf(" [%s] %.0f%%", bar, pct)
+}
+
+// showFileInfo mostra os codecs do arquivo baixado. Com audioOnly, a falta da
+// faixa de vídeo é esperada e não gera aviso.
+func (a *App) showFileInfo(filePath string, audioOnly bool) {
 	probe, err := downloader.ProbeFile(filePath)
 	if err != nil {
 		return
 	}
 
 	fmt.Println()
-	fmt.Println(" Formato do arquivo:")
+	i18n.Println(" Formato do arquivo:")
 	if probe.HasVideo {
-		fmt.Printf("   Vídeo: %s\n", strings.ToUpper(probe.VideoCodec))
+		i18n.Printf("   Vídeo: %s\n", strings.ToUpper(probe.VideoCodec))
 	}
 	if probe.HasAudio {
-		fmt.Printf("   Áudio: %s\n", strings.ToUpper(probe.AudioCodec))
+		i18n.Printf("   Áudio: %s\n", strings.ToUpper(probe.AudioCodec))
 	}
 
 	if !probe.HasVideo && !probe.HasAudio {
 		fmt.Println()
-		fmt.Println(" [AVISO] O arquivo não possui faixas de vídeo nem áudio!")
-		fmt.Println(" O download pode ter falhado. Tente novamente.")
-	} else if !probe.HasVideo {
+		i18n.Println(" [AVISO] O arquivo não possui faixas de vídeo nem áudio!")
+		i18n.Println(" O download pode ter falhado. Tente novamente.")
+	} else if !probe.HasVideo && !audioOnly {
 		fmt.Println()
-		fmt.Println(" [AVISO] O arquivo não possui faixa de vídeo!")
-		fmt.Println(" Pode ser necessário baixar o codec de vídeo ou tentar outra qualidade.")
+		i18n.Println(" [AVISO] O arquivo não possui faixa de vídeo!")
+		i18n.Println(" Pode ser necessário baixar o codec de vídeo ou tentar outra qualidade.")
 	} else if !probe.HasAudio {
 		fmt.Println()
-		fmt.Println(" [AVISO] O arquivo não possui faixa de áudio!")
-		fmt.Println(" O merge pode ter falhado. Verifique se o ffmpeg está instalado corretamente.")
+		i18n.Println(" [AVISO] O arquivo não possui faixa de áudio!")
+		i18n.Println(" O merge pode ter falhado. Verifique se o ffmpeg está instalado corretamente.")
+	}
+}
+
+// downloaderFor retorna o Downloader responsável pela plataforma, ou nil se não houver.
+func (a *App) downloaderFor(p validator.Platform) downloader.Downloader {
+	switch p {
+	case validator.PlatformYouTube:
+		return a.ytDownloader
+	case validator.PlatformFacebook:
+		return a.fbDownloader
+	case validator.PlatformInstagram:
+		return a.igDownloader
+	default:
+		return nil
+	}
+}
+
+// platformOf retorna a plataforma atendida pelo Downloader.
+func (a *App) platformOf(dl downloader.Downloader) validator.Platform {
+	switch dl {
+	case a.ytDownloader:
+		return validator.PlatformYouTube
+	case a.fbDownloader:
+		return validator.PlatformFacebook
+	case a.igDownloader:
+		return validator.PlatformInstagram
+	default:
+		return validator.PlatformUnknown
 	}
 }
 
@@ -359,6 +1098,59 @@ func (a *App) parseChoice(input string, max int) int {
 	return n - 1
 }
 
+// parseSelection interpreta uma escolha múltipla de itens numerados de 1 a max:
+// números e intervalos separados por vírgula ("1,3,5-9") ou "all"/"todos" para
+// todos. Retorna os índices (base 0) em ordem crescente, sem repetição.
+func parseSelection(input string, max int) ([]int, error) {
+	input = strings.ToLower(strings.TrimSpace(input))
+	if input == "all" || input == "todos" || input == "todas" || input == "*" {
+		all := make([]int, max)
+		for i := range all {
+			all[i] = i
+		}
+		return all, nil
+	}
+
+	seen := make(map[int]bool)
+	for _, part := range strings.Split(input, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		lo, hi, isRange := strings.Cut(part, "-")
+		first, err := strconv.Atoi(strings.TrimSpace(lo))
+		last := first
+		if err == nil && isRange {
+			last, err = strconv.Atoi(strings.TrimSpace(hi))
+		}
+		if err != nil {
+			return nil, i18n.Errorf("seleção inválida: %q", part)
+		}
+		if first < 1 || last > max || first > last {
+			return nil, i18n.Errorf("seleção fora do intervalo 1-%d: %q", max, part)
+		}
+		for n := first; n <= last; n++ {
+			seen[n-1] = true
+		}
+	}
+	if len(seen) == 0 {
+		return nil, i18n.Errorf("nenhum item escolhido")
+	}
+
+	picks := make([]int, 0, len(seen))
+	for idx := range seen {
+		picks = append(picks, idx)
+	}
+	sort.Ints(picks)
+	return picks, nil
+}
+
+// isYes indica se a resposta a uma pergunta (s/N) foi afirmativa.
+func isYes(answer string) bool {
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "s" || answer == "sim" || answer == "y" || answer == "yes"
+}
+
 func (a *App) readInput() string {
 	fmt.Print("\n >> ")
 	input, _ := a.reader.ReadString('\n')
@@ -382,8 +1174,8 @@ func (a *App) printSeparator() {
 }
 
 func (a *App) showError(msg string) {
-	fmt.Printf("\n [ERRO] %s\n", msg)
-	fmt.Print(" Pressione ENTER para continuar...")
+	i18n.Printf("\n [ERRO] %s\n", msg)
+	i18n.Print(" Pressione ENTER para continuar...")
 	a.reader.ReadString('\n')
 }
 
@@ -0,0 +1,47 @@
+package cli
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/diogocardoso/DownloaderTube/internal/downloader"
+)
+
+func TestParseSelection(t *testing.T) {
+	cases := map[string][]int{
+		"1,3,5-9":   {0, 2, 4, 5, 6, 7, 8},
+		" 2 , 2,1 ": {0, 1},
+		"9-10,1":    {0, 8, 9},
+		"all":       {0, 1, 2, 3, 4, 5, 6, 7, 8, 9},
+		"TODOS":     {0, 1, 2, 3, 4, 5, 6, 7, 8, 9},
+	}
+	for in, want := range cases {
+		got, err := parseSelection(in, 10)
+		if err != nil || !reflect.DeepEqual(got, want) {
+			t.Errorf("parseSelection(%q) = %v, %v; esperava %v", in, got, err, want)
+		}
+	}
+
+	for _, in := range []string{"", ",", "0", "11", "3-2", "1-11", "a", "1-", "-3", "1,x"} {
+		if got, err := parseSelection(in, 10); err == nil {
+			t.Errorf("parseSelection(%q) deveria falhar, veio %v", in, got)
+		}
+	}
+}
+
+func TestCarouselSelection(t *testing.T) {
+	items := []downloader.CarouselItem{{Index: 1}, {Index: 2, Photo: true}, {Index: 3, Photo: true}}
+
+	if got, err := carouselSelection("3,2", items); err != nil || !reflect.DeepEqual(got, []int{2, 3}) {
+		t.Errorf("carouselSelection(3,2) = %v, %v", got, err)
+	}
+	if got, err := carouselSelection("all", items); err != nil || got != nil {
+		t.Errorf("all deveria virar nil (todos), veio %v, %v", got, err)
+	}
+	if !carouselPhotosOnly(items, []int{2, 3}) {
+		t.Error("itens 2 e 3 são só fotos")
+	}
+	if carouselPhotosOnly(items, nil) || carouselPhotosOnly(nil, nil) {
+		t.Error("o carrossel inteiro tem vídeo; sem carrossel não há fotos")
+	}
+}
@@ -0,0 +1,162 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/diogocardoso/DownloaderTube/internal/downloader"
+	"github.com/diogocardoso/DownloaderTube/internal/i18n"
+)
+
+// processPlaylist lista os vídeos da playlist, pergunta quais baixar e com qual
+// qualidade, e baixa cada um em sequência, numerando os arquivos pela posição
+// na playlist. Ao final mostra o resultado de cada vídeo.
+func (a *App) processPlaylist(url string, lister downloader.PlaylistLister) {
+	a.clearScreen()
+	i18n.Println(" Buscando vídeos da playlist...")
+	a.printSeparator()
+
+	ctx, done := a.foregroundContext()
+	playlist, err := lister.GetPlaylist(ctx, url)
+	done()
+	if err != nil {
+		a.showError(i18n.Sprintf("Erro ao buscar playlist: %v", err))
+		return
+	}
+
+	entries, ok := a.selectPlaylistEntries(playlist)
+	if !ok {
+		return
+	}
+	policy, ok := a.askBatchPolicy(len(entries))
+	if !ok {
+		return
+	}
+
+	if err := a.cfg.EnsureDownloadDir(); err != nil {
+		a.showError(i18n.Sprintf("Erro ao criar pasta de download: %v", err))
+		return
+	}
+
+	a.clearScreen()
+	results := a.runPlaylist(playlist, entries, policy, os.Stdout, false)
+
+	a.clearScreen()
+	i18n.Printf(" Playlist: %s\n", playlist.Title)
+	fmt.Println()
+	printBatchSummary(os.Stdout, results)
+	a.printFooter()
+	i18n.Print("\n Pressione ENTER para continuar...")
+	a.reader.ReadString('\n')
+}
+
+// selectPlaylistEntries mostra os vídeos da playlist e lê a escolha múltipla
+// (ver parseSelection).
+func (a *App) selectPlaylistEntries(playlist *downloader.Playlist) ([]downloader.PlaylistEntry, bool) {
+	for {
+		a.clearScreen()
+		i18n.Printf(" Playlist: %s (%d vídeo(s))\n", playlist.Title, len(playlist.Entries))
+		fmt.Println()
+		for _, e := range playlist.Entries {
+			duration := "--:--"
+			if e.Duration > 0 {
+				duration = downloader.FormatTimestamp(e.Duration)
+			}
+			fmt.Printf(" %3d - [%s] %s\n", e.Index, duration, e.Title)
+		}
+		fmt.Println()
+		i18n.Println(" Escolha os vídeos (ex: 1,3,5-9) ou all para todos.")
+		fmt.Println()
+		i18n.Println(" 0 - Voltar")
+		a.printSeparator()
+
+		choice := a.readInput()
+		if choice == "0" {
+			return nil, false
+		}
+		picks, err := parseSelection(choice, len(playlist.Entries))
+		if err != nil {
+			a.showError(err.Error())
+			continue
+		}
+		entries := make([]downloader.PlaylistEntry, 0, len(picks))
+		for _, idx := range picks {
+			entries = append(entries, playlist.Entries[idx])
+		}
+		return entries, true
+	}
+}
+
+// runPlaylist baixa os vídeos escolhidos com a mesma política, prefixando cada
+// arquivo com a posição na playlist ("03_youtube_<id>.mp4"). Como em runBatch,
+// uma falha não interrompe os demais e um Ctrl+C cancela só o vídeo atual, a
+// menos que stopOnCancel esteja ativo.
+func (a *App) runPlaylist(playlist *downloader.Playlist, entries []downloader.PlaylistEntry, policy downloadPolicy, w io.Writer, stopOnCancel bool) []autoResult {
+	width := 2
+	if n := len(playlist.Entries); n > 0 {
+		width = max(width, len(strconv.Itoa(playlist.Entries[n-1].Index)))
+	}
+
+	results := make([]autoResult, 0, len(entries))
+	for i, e := range entries {
+		fmt.Fprintf(w, "\n [%d/%d] #%d %s\n", i+1, len(entries), e.Index, e.Title)
+		p := policy
+		p.filePrefix = fmt.Sprintf("%0*d_", width, e.Index)
+		res := a.autoDownload(e.URL, p, w)
+		res.Index = e.Index
+		if res.Title == "" {
+			res.Title = e.Title
+		}
+		if res.Err != nil {
+			i18n.Fprintf(w, " [ERRO] %v\n", res.Err)
+		}
+		if a.json != nil {
+			a.json.result(res)
+		}
+		results = append(results, res)
+		if stopOnCancel && isCanceled(res.Err) {
+			break
+		}
+	}
+	return results
+}
+
+// cmdPlaylist é o "get" de uma URL de playlist: baixa os itens de --items
+// (todos por padrão) e imprime o resumo como o "batch".
+func (a *App) cmdPlaylist(url, items string, policy downloadPolicy, lister downloader.PlaylistLister, jsonOut bool) int {
+	ctx, done := a.foregroundContext()
+	playlist, err := lister.GetPlaylist(ctx, url)
+	canceled := ctx.Err() != nil
+	done()
+	if canceled {
+		return ExitCanceled
+	}
+	if err != nil {
+		i18n.Fprintf(os.Stderr, "Erro ao buscar playlist: %v\n", err)
+		return ExitInfoFailed
+	}
+
+	picks, err := parseSelection(items, len(playlist.Entries))
+	if err != nil {
+		i18n.Fprintf(os.Stderr, "Erro: %v\n", err)
+		return ExitUsage
+	}
+	entries := make([]downloader.PlaylistEntry, 0, len(picks))
+	for _, idx := range picks {
+		entries = append(entries, playlist.Entries[idx])
+	}
+
+	var results []autoResult
+	if jsonOut {
+		a.json = newJSONWriter(os.Stdout)
+		results = a.runPlaylist(playlist, entries, policy, io.Discard, true)
+		a.json.summary(results)
+	} else {
+		i18n.Fprintf(os.Stderr, " Playlist: %s\n", playlist.Title)
+		results = a.runPlaylist(playlist, entries, policy, os.Stderr, true)
+		printBatchSummary(os.Stdout, results)
+	}
+	return batchExitCode(results)
+}
@@ -0,0 +1,270 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/diogocardoso/DownloaderTube/internal/downloader"
+	"github.com/diogocardoso/DownloaderTube/internal/i18n"
+	"github.com/diogocardoso/DownloaderTube/internal/queue"
+	"github.com/diogocardoso/DownloaderTube/pkg/validator"
+)
+
+// downloadQueue cria a fila na primeira utilização, com os workers da configuração.
+func (a *App) downloadQueue() *queue.Queue {
+	if a.queue == nil {
+		a.queue = queue.New(a.cfg.QueueWorkers)
+		a.queue.OnFinish = func(j *queue.Job, s queue.Status) {
+			a.recordHistory(j.Downloader, downloadRecord{
+				URL:          j.Request.URL,
+				Title:        j.Title,
+				Height:       j.Request.Height,
+				LangCode:     j.Request.LangCode,
+				ExtraLangs:   j.Request.ExtraLangs,
+				WhatsAppCopy: j.Request.WhatsAppCopy,
+				Audio:        j.Request.Audio,
+				Subtitles:    j.Request.Subtitles,
+				Clip:         j.Request.Clip,
+				Chapters:     j.Request.Chapters,
+				Items:        j.Request.Items,
+				StartedAt:    s.StartedAt,
+				Result:       s.Result,
+				Warnings:     resultWarnings(s.Result),
+				Err:          s.Err,
+				RetryOf:      j.RetryOf,
+			})
+		}
+	}
+	return a.queue
+}
+
+func (a *App) queueMenu() {
+	q := a.downloadQueue()
+
+	for {
+		a.clearScreen()
+		i18n.Printf(" Fila de downloads (%d simultâneo(s))\n", q.Workers())
+		fmt.Println()
+
+		jobs := q.Snapshot()
+		if len(jobs) == 0 {
+			i18n.Println(" Nenhum download na fila.")
+		}
+		for _, s := range jobs {
+			fmt.Println(formatJobLine(s))
+		}
+
+		fmt.Println()
+		i18n.Println(" 1 - Adicionar URL à fila")
+		i18n.Println(" 2 - Acompanhar progresso")
+		i18n.Println(" 3 - Limpar concluídos")
+		i18n.Println(" 4 - Cancelar download")
+		fmt.Println()
+		i18n.Println(" 0 - Voltar")
+		i18n.Println(" x - Sair")
+		a.printSeparator()
+
+		choice := a.readInput()
+
+		switch strings.ToLower(choice) {
+		case "0":
+			return
+		case "x":
+			if !a.confirmExit() {
+				continue
+			}
+			a.shutdown()
+			i18n.Println("\n Até logo!")
+			os.Exit(0)
+		case "1":
+			a.addToQueue(q)
+		case "2":
+			a.watchQueue(q)
+		case "3":
+			q.ClearFinished()
+		case "4":
+			a.cancelQueueJob(q)
+		default:
+			if validator.LooksLikeURL(choice) {
+				// Atalho: colar o link direto na tela da fila.
+				a.enqueueURL(q, choice)
+				continue
+			}
+			a.showError(i18n.T("Opção inválida!"))
+		}
+	}
+}
+
+func (a *App) addToQueue(q *queue.Queue) {
+	a.clearScreen()
+	i18n.Println(" URL do vídeo para adicionar à fila:")
+	fmt.Println()
+	i18n.Println(" 0 - Voltar")
+	a.printSeparator()
+
+	input := a.readInput()
+	if input == "0" || input == "" {
+		return
+	}
+	a.enqueueURL(q, input)
+}
+
+// enqueueURL conduz as telas de idioma e qualidade e coloca o vídeo na fila,
+// devolvendo o controle ao menu sem esperar o download.
+func (a *App) enqueueURL(q *queue.Queue, raw string) {
+	url, dl, ok := a.resolvePastedURL(raw)
+	if !ok {
+		return
+	}
+
+	req, ok := a.prepareVideo(url, dl)
+	if !ok {
+		return
+	}
+	a.enqueue(q, dl, req)
+}
+
+// enqueue coloca um vídeo já preparado na fila e retorna o número do job.
+func (a *App) enqueue(q *queue.Queue, dl downloader.Downloader, req downloadRequest) (int, bool) {
+	if err := a.cfg.EnsureDownloadDir(); err != nil {
+		a.showError(i18n.Sprintf("Erro ao criar pasta de download: %v", err))
+		return 0, false
+	}
+
+	req.Dest = a.cfg.DownloadDir
+	// O limite global é dividido entre os downloads simultâneos da fila.
+	req.RateLimit = a.cfg.JobRateLimit(q.Workers())
+	return q.Add(&queue.Job{
+		Request:    req.DownloadRequest,
+		Title:      req.Title,
+		Label:      req.displayLabel(),
+		Downloader: dl,
+		RetryOf:    req.RetryOf,
+	}), true
+}
+
+// downloadOrQueue pergunta se o vídeo vai para a fila (o padrão), liberando o
+// menu para outros downloads, ou se é baixado agora com o progresso na tela.
+func (a *App) downloadOrQueue(dl downloader.Downloader, req downloadRequest) {
+	for {
+		a.clearScreen()
+		i18n.Printf(" Vídeo: %s [%s]\n", req.Title, req.displayLabel())
+		fmt.Println()
+		i18n.Println(" ENTER - Adicionar à fila e voltar ao menu")
+		i18n.Println(" 1 - Baixar agora e aguardar")
+		fmt.Println()
+		i18n.Println(" 0 - Voltar")
+		a.printSeparator()
+
+		switch a.readInput() {
+		case "":
+			if id, ok := a.enqueue(a.downloadQueue(), dl, req); ok {
+				i18n.Printf("\n Adicionado à fila como #%d. Acompanhe em 5 - Fila de downloads.\n", id)
+				i18n.Print(" Pressione ENTER para continuar...")
+				a.reader.ReadString('\n')
+			}
+			return
+		case "1":
+			a.startDownload(dl, req)
+			return
+		case "0":
+			return
+		default:
+			a.showError(i18n.T("Opção inválida!"))
+		}
+	}
+}
+
+// cancelQueueJob pergunta o número do job e o cancela, removendo os arquivos parciais.
+func (a *App) cancelQueueJob(q *queue.Queue) {
+	i18n.Println("\n Número do download a cancelar (ex: 3). ENTER para voltar:")
+	input := strings.TrimPrefix(a.readInput(), "#")
+	if input == "" {
+		return
+	}
+
+	var id int
+	if _, err := fmt.Sscanf(input, "%d", &id); err != nil || !q.Cancel(id) {
+		a.showError(i18n.T("Download não encontrado ou já finalizado."))
+	}
+}
+
+// watchQueue redesenha uma linha de progresso por job até o usuário pressionar ENTER.
+// Os downloads continuam rodando ao voltar para o menu.
+func (a *App) watchQueue(q *queue.Queue) {
+	a.clearScreen()
+	i18n.Println(" Acompanhando downloads. Pressione ENTER para voltar ao menu.")
+	a.printSeparator()
+
+	done := make(chan struct{})
+	go func() {
+		a.reader.ReadString('\n')
+		close(done)
+	}()
+
+	ansi := enableANSI()
+	ticker := time.NewTicker(500 * time.Millisecond)
+	defer ticker.Stop()
+
+	drawn := 0
+	for {
+		jobs := q.Snapshot()
+		if ansi {
+			if drawn > 0 {
+				fmt.Printf("\033[%dA", drawn)
+			}
+			for _, s := range jobs {
+				fmt.Printf("\033[2K%s\n", formatJobLine(s))
+			}
+			drawn = len(jobs)
+		} else {
+			a.clearScreen()
+			i18n.Println(" Acompanhando downloads. Pressione ENTER para voltar ao menu.")
+			a.printSeparator()
+			for _, s := range jobs {
+				fmt.Println(formatJobLine(s))
+			}
+		}
+
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// confirmExit pergunta antes de sair quando há downloads em andamento na fila.
+func (a *App) confirmExit() bool {
+	if a.queue == nil || a.queue.Active() == 0 {
+		return true
+	}
+	i18n.Printf("\n Há %d download(s) em andamento na fila. Sair mesmo assim? (s/N)", a.queue.Active())
+	return isYes(a.readInput())
+}
+
+func formatJobLine(s queue.Status) string {
+	name := truncate(s.Title, 40)
+	if s.Label != "" {
+		name += " [" + s.Label + "]"
+	}
+
+	switch s.State {
+	case queue.StatePending:
+		return i18n.Sprintf(" #%d aguardando  %s", s.ID, name)
+	case queue.StateRunning:
+		return fmt.Sprintf(" #%d%s  %s", s.ID, formatProgress(s.Progress), name)
+	case queue.StateDone:
+		line := i18n.Sprintf(" #%d concluído  %s -> %s", s.ID, name, s.Result.FilePath)
+		if s.Result.CompatibilityWarning != "" {
+			line += i18n.Sprintf(" [AVISO: %s]", s.Result.CompatibilityWarning)
+		}
+		return line
+	case queue.StateCanceled:
+		return i18n.Sprintf(" #%d cancelado  %s", s.ID, name)
+	default:
+		return i18n.Sprintf(" #%d falhou  %s: %v", s.ID, name, s.Err)
+	}
+}
@@ -0,0 +1,504 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/diogocardoso/DownloaderTube/internal/downloader"
+	"github.com/diogocardoso/DownloaderTube/internal/i18n"
+	"github.com/diogocardoso/DownloaderTube/internal/subscriptions"
+	"github.com/diogocardoso/DownloaderTube/pkg/validator"
+)
+
+// syncResult é o resultado da sincronização de uma assinatura.
+type syncResult struct {
+	Source subscriptions.Source
+	Items  []downloader.DownloadResult
+	Err    error
+}
+
+func (a *App) subscriptionsMenu() {
+	if a.subs == nil {
+		a.showError(i18n.T("Assinaturas indisponíveis: não foi possível determinar a pasta de configuração."))
+		return
+	}
+
+	for {
+		sources, err := a.subs.List()
+		if err != nil {
+			a.showError(err.Error())
+			return
+		}
+
+		a.clearScreen()
+		i18n.Println(" Assinaturas")
+		i18n.Printf(" Arquivo: %s\n", a.subs.Path())
+		fmt.Println()
+		if len(sources) == 0 {
+			i18n.Println(" Nenhuma assinatura cadastrada.")
+		}
+		for i, src := range sources {
+			fmt.Printf(" %d - %s\n", i+1, formatSourceLine(src))
+		}
+
+		fmt.Println()
+		i18n.Println(" n - Nova assinatura")
+		if len(sources) > 0 {
+			i18n.Println(" s - Sincronizar todas")
+		}
+		i18n.Println(" 0 - Voltar")
+		i18n.Println(" x - Sair")
+		a.printSeparator()
+
+		choice := a.readInput()
+
+		switch strings.ToLower(choice) {
+		case "0":
+			return
+		case "x":
+			if !a.confirmExit() {
+				continue
+			}
+			a.shutdown()
+			i18n.Println("\n Até logo!")
+			os.Exit(0)
+		case "n":
+			a.addSubscription()
+		case "s":
+			if len(sources) > 0 {
+				a.syncInteractive(sources)
+			}
+		default:
+			idx := a.parseChoice(choice, len(sources))
+			if idx < 0 {
+				a.showError(i18n.T("Opção inválida!"))
+				continue
+			}
+			a.subscriptionDetail(sources[idx])
+		}
+	}
+}
+
+func (a *App) subscriptionDetail(src subscriptions.Source) {
+	for {
+		a.clearScreen()
+		i18n.Println(" Assinatura")
+		a.printSeparator()
+		if src.Name != "" {
+			i18n.Printf(" Nome: %s\n", src.Name)
+		}
+		fmt.Printf(" URL: %s\n", src.URL)
+		i18n.Printf(" Plataforma: %s\n", validator.Platform(src.Platform).Label())
+		i18n.Printf(" Qualidade: %s\n", sourceQualityLabel(src))
+		i18n.Printf(" A partir de: %s\n", sourceSinceLabel(src))
+		i18n.Printf(" Limite por sincronização: %s\n", sourceLimitLabel(src))
+		if !src.LastSync.IsZero() {
+			i18n.Printf(" Última sincronização: %s (%d novo(s))\n", src.LastSync.Local().Format("02/01/2006 15:04"), src.LastCount)
+		}
+		if src.LastError != "" {
+			i18n.Printf(" [AVISO] Última falha: %s\n", src.LastError)
+		}
+		fmt.Println()
+		i18n.Println(" s - Sincronizar agora")
+		i18n.Println(" e - Editar")
+		i18n.Println(" r - Remover")
+		i18n.Println(" 0 - Voltar")
+		a.printSeparator()
+
+		switch strings.ToLower(a.readInput()) {
+		case "0":
+			return
+		case "s":
+			a.syncInteractive([]subscriptions.Source{src})
+			return
+		case "e":
+			if edited, ok := a.askSourceSettings(src); ok {
+				if err := a.subs.Update(edited); err != nil {
+					a.showError(err.Error())
+					continue
+				}
+				src = edited
+			}
+		case "r":
+			i18n.Printf("\n Remover %s? (s/N)\n", src.Label())
+			if !isYes(a.readInput()) {
+				continue
+			}
+			if err := a.subs.Remove(src.ID); err != nil {
+				a.showError(err.Error())
+				continue
+			}
+			return
+		default:
+			a.showError(i18n.T("Opção inválida!"))
+		}
+	}
+}
+
+// addSubscription pede a URL do canal ou perfil e as configurações da nova assinatura.
+func (a *App) addSubscription() {
+	a.clearScreen()
+	i18n.Println(" Nova assinatura")
+	fmt.Println()
+	i18n.Println(" URL do canal do YouTube (ex: youtube.com/@canal/videos) ou do perfil do Instagram:")
+	raw := a.readInput()
+	if raw == "" || raw == "0" {
+		return
+	}
+
+	matches := validator.Classify(raw)
+	if len(matches) != 1 || !subscribable(matches[0]) {
+		a.showError(i18n.T("URL inválida! Informe um canal do YouTube ou um perfil do Instagram."))
+		return
+	}
+
+	i18n.Println("\n Nome para exibição (ENTER para usar a URL):")
+	src := subscriptions.Source{
+		URL:      matches[0].URL,
+		Platform: string(matches[0].Platform),
+		Name:     a.readInput(),
+		// Sem data, a primeira sincronização baixaria o canal inteiro.
+		Since: time.Now().Format("2006-01-02"),
+	}
+	src, ok := a.askSourceSettings(src)
+	if !ok {
+		return
+	}
+	if _, err := a.subs.Add(src); err != nil {
+		a.showError(err.Error())
+	}
+}
+
+// askSourceSettings pergunta qualidade, data inicial e limite da assinatura;
+// ENTER mantém o valor atual de cada um.
+func (a *App) askSourceSettings(src subscriptions.Source) (subscriptions.Source, bool) {
+	i18n.Printf("\n Qualidade (atual: %s)\n", sourceQualityLabel(src))
+	i18n.Println(" Preset (best, 720p, whatsapp), altura máxima (ex: 1080) ou áudio (mp3, m4a, opus); - usa o padrão.")
+	if input := a.readInput(); input == "-" {
+		src.Quality = ""
+	} else if input != "" {
+		if err := validateSourceQuality(input); err != nil {
+			a.showError(err.Error())
+			return src, false
+		}
+		src.Quality = input
+	}
+
+	i18n.Printf("\n Baixar itens publicados a partir de (AAAA-MM-DD; atual: %s)\n", sourceSinceLabel(src))
+	i18n.Println(" - remove o filtro de data.")
+	if input := a.readInput(); input == "-" {
+		src.Since = ""
+	} else if input != "" {
+		if _, err := subscriptions.ParseDate(input); err != nil {
+			a.showError(err.Error())
+			return src, false
+		}
+		src.Since = input
+	}
+
+	i18n.Printf("\n Máximo de itens verificados por sincronização (atual: %s; 0 = sem limite)\n", sourceLimitLabel(src))
+	if input := a.readInput(); input != "" {
+		n, err := strconv.Atoi(input)
+		if err != nil || n < 0 {
+			a.showError(i18n.T("Número inválido!"))
+			return src, false
+		}
+		src.Limit = n
+	}
+	return src, true
+}
+
+// syncInteractive sincroniza as assinaturas e mostra o relatório.
+func (a *App) syncInteractive(sources []subscriptions.Source) {
+	if err := a.cfg.EnsureDownloadDir(); err != nil {
+		a.showError(i18n.Sprintf("Erro ao criar pasta de download: %v", err))
+		return
+	}
+
+	a.clearScreen()
+	i18n.Println(" Ctrl+C cancela a assinatura atual.")
+	results := a.runSync(sources, os.Stdout, false)
+
+	a.clearScreen()
+	printSyncReport(os.Stdout, results)
+	a.printFooter()
+	i18n.Print("\n Pressione ENTER para continuar...")
+	a.reader.ReadString('\n')
+}
+
+// runSync sincroniza as assinaturas em sequência. Uma falha não interrompe as
+// demais; um Ctrl+C cancela só a atual, a menos que stopOnCancel esteja ativo.
+func (a *App) runSync(sources []subscriptions.Source, w io.Writer, stopOnCancel bool) []syncResult {
+	results := make([]syncResult, 0, len(sources))
+	for i, src := range sources {
+		fmt.Fprintf(w, "\n [%d/%d] %s\n", i+1, len(sources), src.Label())
+		res := a.syncSource(src, w)
+		if res.Err != nil {
+			i18n.Fprintf(w, " [ERRO] %v\n", res.Err)
+		}
+		if a.json != nil {
+			a.json.sync(res)
+		}
+		results = append(results, res)
+		if stopOnCancel && isCanceled(res.Err) {
+			break
+		}
+	}
+	return results
+}
+
+// syncSource baixa os itens novos de uma assinatura, registra cada um no
+// histórico e grava o resultado na assinatura.
+func (a *App) syncSource(src subscriptions.Source, w io.Writer) syncResult {
+	res := syncResult{Source: src}
+	dl := a.downloaderFor(validator.Platform(src.Platform))
+	collector, ok := dl.(downloader.CollectionDownloader)
+	if !ok {
+		res.Err = errUnsupportedURL
+		return res
+	}
+	req, err := a.sourceRequest(src)
+	if err != nil {
+		res.Err = err
+		return res
+	}
+
+	res.Items, res.Err = a.downloadCollection(dl, collector, req, src.Label(), w)
+	if isCanceled(res.Err) {
+		return res
+	}
+
+	src.LastSync = time.Now()
+	src.LastCount = len(res.Items)
+	src.LastError = ""
+	if res.Err != nil {
+		src.LastError = res.Err.Error()
+	}
+	if err := a.subs.Update(src); err != nil {
+		i18n.Fprintf(w, " [AVISO] Não foi possível salvar a assinatura: %v\n", err)
+	}
+	res.Source = src
+	return res
+}
+
+// sourceRequest monta o pedido de sincronização da assinatura, com o arquivo
+// de downloads já registrados compartilhado por todas.
+func (a *App) sourceRequest(src subscriptions.Source) (downloader.CollectionRequest, error) {
+	policy, err := parseQualityChoice(src.Quality)
+	if err != nil {
+		return downloader.CollectionRequest{}, err
+	}
+	since, err := src.SinceDate()
+	if err != nil {
+		return downloader.CollectionRequest{}, err
+	}
+
+	req := a.collectionRequest(src.URL, validator.Platform(src.Platform), policy)
+	req.Archive = a.subs.ArchivePath()
+	req.DateAfter = since
+	req.Limit = src.Limit
+	return req, nil
+}
+
+// validateSourceQuality aceita as mesmas qualidades do lote, exceto o preset
+// "worst": sem a lista de formatos de cada vídeo, só há limite de altura.
+func validateSourceQuality(input string) error {
+	policy, err := parseQualityChoice(input)
+	if err != nil {
+		return err
+	}
+	if policy.Preset == downloader.PresetWorst {
+		return i18n.Errorf("o preset %s não vale para assinaturas", downloader.PresetWorst)
+	}
+	return nil
+}
+
+// subscribable indica se a URL é um canal do YouTube ou um perfil, aba de
+// reels, stories ou destaque do Instagram.
+func subscribable(m validator.Match) bool {
+	switch m.Platform {
+	case validator.PlatformYouTube:
+		return true
+	case validator.PlatformInstagram:
+		return validator.IsInstagramCollectionURL(m.URL)
+	}
+	return false
+}
+
+func formatSourceLine(src subscriptions.Source) string {
+	last := i18n.T("nunca sincronizada")
+	if !src.LastSync.IsZero() {
+		last = i18n.Sprintf("%s, %d novo(s)", src.LastSync.Local().Format("02/01 15:04"), src.LastCount)
+	}
+	status := ""
+	if src.LastError != "" {
+		status = " " + i18n.T("[FALHA]")
+	}
+	return fmt.Sprintf("[%s] %s - %s (%s)%s", validator.Platform(src.Platform).Label(), truncate(src.Label(), 40), sourceQualityLabel(src), last, status)
+}
+
+func sourceQualityLabel(src subscriptions.Source) string {
+	if src.Quality == "" {
+		return i18n.T("padrão")
+	}
+	if p, ok := downloader.ParseQualityPreset(src.Quality); ok {
+		return p.Label()
+	}
+	return src.Quality
+}
+
+func sourceSinceLabel(src subscriptions.Source) string {
+	if src.Since == "" {
+		return i18n.T("sem filtro")
+	}
+	return src.Since
+}
+
+func sourceLimitLabel(src subscriptions.Source) string {
+	if src.Limit <= 0 {
+		return i18n.T("sem limite")
+	}
+	return strconv.Itoa(src.Limit)
+}
+
+// printSyncReport imprime o que cada assinatura baixou e as falhas.
+func printSyncReport(w io.Writer, results []syncResult) {
+	var fetched, failed int
+
+	i18n.Fprintln(w, " Relatório da sincronização")
+	fmt.Fprintln(w, " -------------------------------")
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	i18n.Fprintln(tw, " #\tStatus\tAssinatura\tNovos")
+	for i, r := range results {
+		status := "OK"
+		switch {
+		case isCanceled(r.Err):
+			status = i18n.T("CANCELADO")
+			failed++
+		case r.Err != nil:
+			status = i18n.T("FALHA")
+			failed++
+		}
+		fetched += len(r.Items)
+		fmt.Fprintf(tw, " %d\t%s\t%s\t%d\n", i+1, status, truncate(r.Source.Label(), 40), len(r.Items))
+	}
+	tw.Flush()
+
+	for i, r := range results {
+		if len(r.Items) == 0 && r.Err == nil {
+			continue
+		}
+		fmt.Fprintln(w)
+		fmt.Fprintf(w, " #%d %s\n", i+1, r.Source.Label())
+		for _, item := range r.Items {
+			fmt.Fprintf(w, "   %s\n", filepath.Base(item.FilePath))
+		}
+		if r.Err != nil {
+			i18n.Fprintf(w, "   [ERRO] %v\n", r.Err)
+		}
+	}
+
+	fmt.Fprintln(w)
+	i18n.Fprintf(w, " Novos: %d | Assinaturas com falha: %d\n", fetched, failed)
+}
+
+// cmdSync é o comando "sync": sincroniza todas as assinaturas (ou as
+// indicadas por número, ID ou nome) e imprime o relatório, para uso no cron.
+func (a *App) cmdSync(args []string) int {
+	fs := flag.NewFlagSet("sync", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	limitRate := fs.String("limit-rate", "", i18n.T("velocidade máxima do download (ex: 500K, 2M); vence a configuração"))
+	out := fs.String("out", "", i18n.Sprintf("pasta de destino (padrão: %s)", a.cfg.DownloadDir))
+	jsonOut := fs.Bool("json", false, i18n.T("emite progresso e resultado de cada assinatura como linhas JSON em stdout"))
+
+	positional, err := parseInterspersed(fs, args)
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return ExitOK
+		}
+		return ExitUsage
+	}
+	if err := a.applyRateFlag(*limitRate); err != nil {
+		i18n.Fprintf(os.Stderr, "Erro: %v\n", err)
+		return ExitUsage
+	}
+	if a.subs == nil {
+		i18n.Fprintln(os.Stderr, "Erro: assinaturas indisponíveis (pasta de configuração desconhecida).")
+		return ExitFailure
+	}
+
+	sources, err := a.subs.List()
+	if err != nil {
+		i18n.Fprintf(os.Stderr, "Erro: %v\n", err)
+		return ExitFailure
+	}
+	if len(sources) == 0 {
+		i18n.Fprintf(os.Stderr, "Nenhuma assinatura cadastrada em %s.\n", a.subs.Path())
+		return ExitUsage
+	}
+	if len(positional) > 0 {
+		sources, err = pickSources(sources, positional)
+		if err != nil {
+			i18n.Fprintf(os.Stderr, "Erro: %v\n", err)
+			return ExitUsage
+		}
+	}
+
+	if !a.ensureDependencies() {
+		return ExitFailure
+	}
+	if *out != "" {
+		a.cfg.DownloadDir = *out
+	}
+	if err := a.cfg.EnsureDownloadDir(); err != nil {
+		i18n.Fprintf(os.Stderr, "Erro ao criar pasta de download: %v\n", err)
+		return ExitFailure
+	}
+
+	var results []syncResult
+	if *jsonOut {
+		a.json = newJSONWriter(os.Stdout)
+		results = a.runSync(sources, io.Discard, true)
+	} else {
+		results = a.runSync(sources, os.Stderr, true)
+		printSyncReport(os.Stdout, results)
+	}
+
+	for _, r := range results {
+		if isCanceled(r.Err) {
+			return ExitCanceled
+		}
+		if r.Err != nil {
+			return ExitDownloadFailed
+		}
+	}
+	return ExitOK
+}
+
+// pickSources seleciona as assinaturas pelo número na lista, ID ou nome.
+func pickSources(sources []subscriptions.Source, keys []string) ([]subscriptions.Source, error) {
+	var picked []subscriptions.Source
+	for _, key := range keys {
+		found := false
+		for i, src := range sources {
+			if key == strconv.Itoa(i+1) || key == src.ID || strings.EqualFold(key, src.Name) {
+				picked = append(picked, src)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, i18n.Errorf("assinatura %s não encontrada", key)
+		}
+	}
+	return picked, nil
+}
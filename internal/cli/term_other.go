@@ -0,0 +1,9 @@
+//go:build !windows
+
+package cli
+
+// enableANSI não precisa fazer nada fora do Windows: os terminais já
+// interpretam sequências ANSI.
+func enableANSI() bool {
+	return true
+}
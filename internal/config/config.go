@@ -1,27 +1,452 @@
 package config
 
 import (
+	"maps"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/diogocardoso/DownloaderTube/internal/downloader"
+	"github.com/diogocardoso/DownloaderTube/internal/i18n"
+)
+
+// Variáveis de ambiente que sobrepõem o arquivo de configuração.
+const (
+	configPathEnv         = "DT_CONFIG"
+	downloadDirEnv        = "DT_DOWNLOAD_DIR"
+	languageEnv           = "DT_LANG"
+	defaultQualityEnv     = "DT_DEFAULT_QUALITY"
+	preferredLanguagesEnv = "DT_PREFERRED_LANGUAGES"
+	cookiesFromBrowserEnv = "DT_COOKIES_FROM_BROWSER"
+	cookiesFileEnv        = "DT_COOKIES_FILE"
+	ytExtractorArgsEnv    = "DT_YT_EXTRACTOR_ARGS"
+	proxyEnv              = "DT_PROXY"
+	ytDlpChannelEnv       = "DT_YTDLP_CHANNEL"
+	queueWorkersEnv       = "DT_QUEUE_WORKERS"
+	rateLimitEnv          = "DT_RATE_LIMIT"
+	rateLimitPerJobEnv    = "DT_RATE_LIMIT_PER_JOB"
+	transcodeWorkersEnv   = "DT_TRANSCODE_WORKERS"
+	transcodePresetEnv    = "DT_TRANSCODE_PRESET"
+	transcodeCRFEnv       = "DT_TRANSCODE_CRF"
+	transcodeDisabledEnv  = "DT_TRANSCODE_DISABLED"
+	skipQualityScreenEnv  = "DT_SKIP_QUALITY_SCREEN"
 )
 
+const (
+	defaultQueueWorkers     = 2
+	defaultTranscodeWorkers = 1
+	defaultTranscodePreset  = "veryfast"
+	defaultTranscodeCRF     = 23
+
+	YtDlpChannelNightly = "nightly"
+	YtDlpChannelStable  = "stable"
+)
+
+// Settings são as opções persistidas no arquivo de configuração. Valores
+// zerados significam "usar o padrão".
+type Settings struct {
+	// DownloadDir é a pasta onde os vídeos são salvos.
+	DownloadDir string `json:"download_dir,omitempty"`
+	// Language é o idioma da interface (ex: "en", "pt-BR"). Vazio usa o idioma
+	// do sistema (LC_ALL/LC_MESSAGES/LANG).
+	Language string `json:"language,omitempty"`
+	// DefaultQuality é a altura máxima usada quando nenhuma é informada; 0 = melhor disponível.
+	DefaultQuality int `json:"default_quality,omitempty"`
+	// QualityPresets é o preset de qualidade padrão de cada plataforma
+	// ("youtube", "facebook", "instagram"), ex: {"instagram": "whatsapp"}.
+	QualityPresets map[string]string `json:"quality_presets,omitempty"`
+	// SkipQualityScreen pula a tela de qualidade quando o preset padrão da
+	// plataforma pode ser atendido pelo vídeo.
+	SkipQualityScreen bool `json:"skip_quality_screen,omitempty"`
+	// PreferredLanguages são os idiomas de áudio preferidos, em ordem.
+	PreferredLanguages []string `json:"preferred_languages,omitempty"`
+	// CookiesFromBrowser é o navegador de onde o yt-dlp lê os cookies das
+	// plataformas sem configuração própria em Cookies.
+	CookiesFromBrowser string `json:"cookies_from_browser,omitempty"`
+	// CookiesFile é um cookies.txt (formato Netscape) usado como o
+	// CookiesFromBrowser; quando os dois estão definidos, vale o arquivo.
+	CookiesFile string `json:"cookies_file,omitempty"`
+	// Cookies define os cookies de uma plataforma ("youtube", "facebook",
+	// "instagram"), no lugar dos globais. Uma entrada vazia desativa os
+	// cookies na plataforma.
+	Cookies map[string]CookieSettings `json:"cookies,omitempty"`
+	// Proxy é o proxy (http, https ou socks5) usado pelo yt-dlp nas
+	// plataformas sem configuração própria em Proxies e no download das
+	// dependências.
+	Proxy string `json:"proxy,omitempty"`
+	// Proxies define o proxy de uma plataforma ("youtube", "facebook",
+	// "instagram"), no lugar do global. Vazio desativa o proxy na plataforma.
+	Proxies map[string]string `json:"proxies,omitempty"`
+	// YouTubeExtractorArgs é repassado ao yt-dlp em --extractor-args.
+	YouTubeExtractorArgs string `json:"youtube_extractor_args,omitempty"`
+	// YtDlpChannel é o canal do yt-dlp gerenciado: "nightly" ou "stable".
+	YtDlpChannel string `json:"ytdlp_channel,omitempty"`
+	// QueueWorkers é a quantidade de downloads simultâneos na fila.
+	QueueWorkers int `json:"queue_workers,omitempty"`
+	// RateLimit é a velocidade máxima somando os downloads simultâneos (ex:
+	// "2M"); a fila divide o valor entre os seus workers.
+	RateLimit string `json:"rate_limit,omitempty"`
+	// RateLimitPerJob é a velocidade máxima de cada download (ex: "500K").
+	RateLimitPerJob string `json:"rate_limit_per_job,omitempty"`
+
+	Transcode TranscodeSettings `json:"transcode"`
+}
+
+// TranscodeSettings controla a conversão para MP4 H.264/AAC com ffmpeg.
+type TranscodeSettings struct {
+	// Disabled desliga a conversão: o arquivo fica no formato entregue pelo yt-dlp.
+	Disabled bool `json:"disabled,omitempty"`
+	// Workers limita as conversões com ffmpeg rodando ao mesmo tempo.
+	Workers int `json:"workers,omitempty"`
+	// Preset é o -preset do libx264 (ex: veryfast, medium).
+	Preset string `json:"preset,omitempty"`
+	// CRF é a qualidade do libx264 (1-51, menor = melhor; 0 usa o padrão).
+	CRF int `json:"crf,omitempty"`
+}
+
+// CookieSettings é a origem dos cookies de uma plataforma: um navegador ou um
+// arquivo cookies.txt, que tem precedência.
+type CookieSettings struct {
+	Browser string `json:"browser,omitempty"`
+	File    string `json:"file,omitempty"`
+}
+
+// Config é a configuração efetiva da aplicação. A precedência, do mais fraco
+// para o mais forte, é: padrões < arquivo < variáveis de ambiente < flags da
+// linha de comando (aplicadas pelo modo não interativo).
 type Config struct {
-	DownloadDir string
-	AppName     string
-	Copyright   string
+	Settings
+
+	AppName   string
+	Copyright string
+
+	path string
+	// file guarda os valores como estão no arquivo, sem as sobreposições do
+	// ambiente, para que Save não grave valores vindos de variáveis.
+	file Settings
+	// overrides mapeia o campo (nome JSON) para a variável que o sobrepôs.
+	overrides map[string]string
 }
 
+// New retorna a configuração padrão com as variáveis de ambiente aplicadas,
+// sem ler o arquivo.
 func New() *Config {
+	c := &Config{
+		AppName:   "Downloader Tube",
+		Copyright: "@Copyright - https://webadvance.com.br | Diogo-dev",
+	}
+	c.apply(Settings{})
+	return c
+}
+
+// Defaults retorna os valores usados quando o arquivo não define um campo.
+func Defaults() Settings {
 	home, _ := os.UserHomeDir()
-	downloadDir := filepath.Join(home, "Downloads", "DownloaderTube")
+	return Settings{
+		DownloadDir:  filepath.Join(home, "Downloads", "DownloaderTube"),
+		YtDlpChannel: YtDlpChannelNightly,
+		QueueWorkers: defaultQueueWorkers,
+		Transcode: TranscodeSettings{
+			Workers: defaultTranscodeWorkers,
+			Preset:  defaultTranscodePreset,
+			CRF:     defaultTranscodeCRF,
+		},
+	}
+}
+
+// Path retorna o arquivo de configuração em uso.
+func (c *Config) Path() string {
+	return c.path
+}
+
+// File retorna uma cópia dos valores gravados no arquivo, sem as sobreposições
+// do ambiente.
+func (c *Config) File() Settings {
+	s := c.file
+	s.PreferredLanguages = append([]string(nil), s.PreferredLanguages...)
+	s.QualityPresets = maps.Clone(s.QualityPresets)
+	s.Cookies = maps.Clone(s.Cookies)
+	s.Proxies = maps.Clone(s.Proxies)
+	return s
+}
+
+// QualityPreset retorna o preset padrão da plataforma, ou "" se não houver.
+func (s Settings) QualityPreset(platform string) downloader.QualityPreset {
+	p, _ := downloader.ParseQualityPreset(s.QualityPresets[platform])
+	return p
+}
 
-	return &Config{
-		DownloadDir: downloadDir,
-		AppName:     "Downloader Tube",
-		Copyright:   "@Copyright - https://webadvance.com.br | Diogo-dev",
+// PlatformCookies retorna os cookies usados na plataforma: os dela, se
+// houver, ou os globais.
+func (s Settings) PlatformCookies(platform string) downloader.Cookies {
+	cs, ok := s.Cookies[platform]
+	if !ok {
+		cs = CookieSettings{Browser: s.CookiesFromBrowser, File: s.CookiesFile}
 	}
+	file := cs.File
+	if file != "" {
+		file = ExpandHome(file)
+	}
+	return downloader.Cookies{Browser: cs.Browser, File: file}
+}
+
+// OverriddenBy retorna a variável de ambiente que sobrepôs o campo (pelo nome
+// JSON, ex: "download_dir"), ou "" se o valor veio do arquivo ou do padrão.
+func (c *Config) OverriddenBy(field string) string {
+	return c.overrides[field]
 }
 
 func (c *Config) EnsureDownloadDir() error {
 	return os.MkdirAll(c.DownloadDir, os.ModePerm)
 }
+
+// CheckDownloadDir cria a pasta, se preciso, e confere se é possível gravar nela.
+func CheckDownloadDir(dir string) error {
+	if !filepath.IsAbs(dir) {
+		return i18n.LazyErrorf("informe o caminho completo da pasta")
+	}
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return i18n.LazyErrorf("não foi possível criar a pasta: %w", err)
+	}
+	f, err := os.CreateTemp(dir, ".downloadertube-*")
+	if err != nil {
+		return i18n.LazyErrorf("sem permissão de escrita na pasta: %w", err)
+	}
+	f.Close()
+	os.Remove(f.Name())
+	return nil
+}
+
+// PlatformProxy retorna o proxy usado na plataforma: o dela, se houver, ou o
+// global.
+func (s Settings) PlatformProxy(platform string) string {
+	if p, ok := s.Proxies[platform]; ok {
+		return p
+	}
+	return s.Proxy
+}
+
+// JobRateLimit retorna o limite, em bytes por segundo, de cada um de
+// concurrent downloads simultâneos: o global dividido entre eles, sem passar do
+// limite por download. 0 = sem limite.
+func (s Settings) JobRateLimit(concurrent int) int64 {
+	global, _ := downloader.ParseRate(s.RateLimit)
+	perJob, _ := downloader.ParseRate(s.RateLimitPerJob)
+	if global > 0 && concurrent > 1 {
+		global /= int64(concurrent)
+	}
+	switch {
+	case global == 0:
+		return perJob
+	case perJob == 0:
+		return global
+	}
+	return min(global, perJob)
+}
+
+// CheckCookiesFile confere se o cookies.txt existe e pode ser lido.
+func CheckCookiesFile(path string) error {
+	if !filepath.IsAbs(path) {
+		return i18n.LazyErrorf("informe o caminho completo do arquivo")
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return i18n.LazyErrorf("não foi possível abrir o arquivo: %w", err)
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return i18n.LazyErrorf("não foi possível abrir o arquivo: %w", err)
+	}
+	if info.IsDir() {
+		return i18n.LazyErrorf("o caminho é uma pasta, não um arquivo cookies.txt")
+	}
+	return nil
+}
+
+// ExpandHome troca um "~" inicial pela pasta do usuário.
+func ExpandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") && !strings.HasPrefix(path, `~\`) {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, path[1:])
+}
+
+// apply recalcula os valores efetivos: padrões, depois o arquivo, depois o ambiente.
+func (c *Config) apply(file Settings) {
+	c.file = file
+	c.overrides = make(map[string]string)
+	c.Settings = merge(Defaults(), file)
+
+	if v := envString(downloadDirEnv); v != "" {
+		c.DownloadDir = v
+		c.overrides["download_dir"] = downloadDirEnv
+	}
+	if v := envString(languageEnv); v != "" {
+		c.Language = v
+		c.overrides["language"] = languageEnv
+	}
+	if v, ok := envInt(defaultQualityEnv, 0); ok {
+		c.DefaultQuality = v
+		c.overrides["default_quality"] = defaultQualityEnv
+	}
+	if v := envString(preferredLanguagesEnv); v != "" {
+		c.PreferredLanguages = SplitList(v)
+		c.overrides["preferred_languages"] = preferredLanguagesEnv
+	}
+	if v := envString(cookiesFromBrowserEnv); v != "" {
+		// O navegador do ambiente vale mesmo com um cookies_file no arquivo.
+		c.CookiesFromBrowser = v
+		c.CookiesFile = ""
+		c.overrides["cookies_from_browser"] = cookiesFromBrowserEnv
+	}
+	if v := envString(cookiesFileEnv); v != "" {
+		c.CookiesFile = v
+		c.overrides["cookies_file"] = cookiesFileEnv
+	}
+	if v := envString(proxyEnv); v != "" {
+		if _, err := downloader.ParseProxy(v); err == nil {
+			c.Proxy = v
+			c.overrides["proxy"] = proxyEnv
+		}
+	}
+	if v := envString(ytExtractorArgsEnv); v != "" {
+		c.YouTubeExtractorArgs = v
+		c.overrides["youtube_extractor_args"] = ytExtractorArgsEnv
+	}
+	if v := strings.ToLower(envString(ytDlpChannelEnv)); v == YtDlpChannelNightly || v == YtDlpChannelStable {
+		c.YtDlpChannel = v
+		c.overrides["ytdlp_channel"] = ytDlpChannelEnv
+	}
+	if v, ok := envInt(queueWorkersEnv, 1); ok {
+		c.QueueWorkers = v
+		c.overrides["queue_workers"] = queueWorkersEnv
+	}
+	if v := envString(rateLimitEnv); v != "" {
+		if _, err := downloader.ParseRate(v); err == nil {
+			c.RateLimit = v
+			c.overrides["rate_limit"] = rateLimitEnv
+		}
+	}
+	if v := envString(rateLimitPerJobEnv); v != "" {
+		if _, err := downloader.ParseRate(v); err == nil {
+			c.RateLimitPerJob = v
+			c.overrides["rate_limit_per_job"] = rateLimitPerJobEnv
+		}
+	}
+	if v, ok := envInt(transcodeWorkersEnv, 1); ok {
+		c.Transcode.Workers = v
+		c.overrides["transcode.workers"] = transcodeWorkersEnv
+	}
+	if v := envString(transcodePresetEnv); validPreset(v) {
+		c.Transcode.Preset = v
+		c.overrides["transcode.preset"] = transcodePresetEnv
+	}
+	if v, ok := envInt(transcodeCRFEnv, 1); ok && v <= 51 {
+		c.Transcode.CRF = v
+		c.overrides["transcode.crf"] = transcodeCRFEnv
+	}
+	if v, err := strconv.ParseBool(envString(skipQualityScreenEnv)); err == nil {
+		c.SkipQualityScreen = v
+		c.overrides["skip_quality_screen"] = skipQualityScreenEnv
+	}
+	if v, err := strconv.ParseBool(envString(transcodeDisabledEnv)); err == nil {
+		c.Transcode.Disabled = v
+		c.overrides["transcode.disabled"] = transcodeDisabledEnv
+	}
+}
+
+// merge preenche base com os campos definidos em file.
+func merge(base, file Settings) Settings {
+	if file.DownloadDir != "" {
+		base.DownloadDir = file.DownloadDir
+	}
+	if file.Language != "" {
+		base.Language = file.Language
+	}
+	if file.DefaultQuality > 0 {
+		base.DefaultQuality = file.DefaultQuality
+	}
+	if len(file.QualityPresets) > 0 {
+		base.QualityPresets = maps.Clone(file.QualityPresets)
+	}
+	if file.SkipQualityScreen {
+		base.SkipQualityScreen = true
+	}
+	if len(file.PreferredLanguages) > 0 {
+		base.PreferredLanguages = append([]string(nil), file.PreferredLanguages...)
+	}
+	if file.CookiesFromBrowser != "" {
+		base.CookiesFromBrowser = file.CookiesFromBrowser
+	}
+	if file.CookiesFile != "" {
+		base.CookiesFile = file.CookiesFile
+	}
+	if len(file.Cookies) > 0 {
+		base.Cookies = maps.Clone(file.Cookies)
+	}
+	if file.Proxy != "" {
+		base.Proxy = file.Proxy
+	}
+	if len(file.Proxies) > 0 {
+		base.Proxies = maps.Clone(file.Proxies)
+	}
+	if file.YouTubeExtractorArgs != "" {
+		base.YouTubeExtractorArgs = file.YouTubeExtractorArgs
+	}
+	if file.YtDlpChannel != "" {
+		base.YtDlpChannel = file.YtDlpChannel
+	}
+	if file.QueueWorkers > 0 {
+		base.QueueWorkers = file.QueueWorkers
+	}
+	if file.RateLimit != "" {
+		base.RateLimit = file.RateLimit
+	}
+	if file.RateLimitPerJob != "" {
+		base.RateLimitPerJob = file.RateLimitPerJob
+	}
+	if file.Transcode.Disabled {
+		base.Transcode.Disabled = true
+	}
+	if file.Transcode.Workers > 0 {
+		base.Transcode.Workers = file.Transcode.Workers
+	}
+	if file.Transcode.Preset != "" {
+		base.Transcode.Preset = file.Transcode.Preset
+	}
+	if file.Transcode.CRF > 0 {
+		base.Transcode.CRF = file.Transcode.CRF
+	}
+	return base
+}
+
+// SplitList separa uma lista de valores por vírgula ou espaço, descartando vazios.
+func SplitList(raw string) []string {
+	return strings.FieldsFunc(raw, func(r rune) bool {
+		return r == ',' || r == ' ' || r == ';'
+	})
+}
+
+func envString(name string) string {
+	return strings.TrimSpace(os.Getenv(name))
+}
+
+// envInt lê um inteiro >= min da variável. Valores ausentes ou inválidos são
+// ignorados (ok=false), mantendo o valor do arquivo ou o padrão.
+func envInt(name string, min int) (int, bool) {
+	raw := envString(name)
+	if raw == "" {
+		return 0, false
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil || n < min {
+		return 0, false
+	}
+	return n, true
+}
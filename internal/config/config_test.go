@@ -0,0 +1,240 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func clearEnv(t *testing.T) {
+	for _, name := range []string{
+		downloadDirEnv, languageEnv, defaultQualityEnv, preferredLanguagesEnv,
+		cookiesFromBrowserEnv, cookiesFileEnv, proxyEnv, ytExtractorArgsEnv, ytDlpChannelEnv, queueWorkersEnv,
+		transcodeWorkersEnv, transcodePresetEnv, transcodeCRFEnv, rateLimitEnv, rateLimitPerJobEnv,
+	} {
+		t.Setenv(name, "")
+	}
+}
+
+func writeConfig(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	clearEnv(t)
+	path := writeConfig(t, `{
+		"version": 1,
+		"download_dir": "/arquivo",
+		"default_quality": 720,
+		"ytdlp_channel": "stable",
+		"transcode": {"preset": "medium"}
+	}`)
+	t.Setenv(downloadDirEnv, "/ambiente")
+
+	c, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if c.DownloadDir != "/ambiente" {
+		t.Errorf("variável deveria vencer o arquivo, veio %q", c.DownloadDir)
+	}
+	if c.DefaultQuality != 720 || c.YtDlpChannel != YtDlpChannelStable || c.Transcode.Preset != "medium" {
+		t.Errorf("valores do arquivo não aplicados: %+v", c.Settings)
+	}
+	if c.Transcode.CRF != defaultTranscodeCRF || c.QueueWorkers != defaultQueueWorkers {
+		t.Errorf("campos ausentes deveriam usar o padrão: %+v", c.Settings)
+	}
+	if got := c.OverriddenBy("download_dir"); got != downloadDirEnv {
+		t.Errorf("OverriddenBy = %q", got)
+	}
+	if got := c.File().DownloadDir; got != "/arquivo" {
+		t.Errorf("File() deveria manter o valor do arquivo, veio %q", got)
+	}
+}
+
+func TestLoadMissingAndInvalid(t *testing.T) {
+	clearEnv(t)
+
+	c, err := Load(filepath.Join(t.TempDir(), "nao-existe.json"))
+	if err != nil || c == nil {
+		t.Fatalf("arquivo inexistente não deveria ser erro: %v", err)
+	}
+
+	c, err = Load(writeConfig(t, `{"version": 99}`))
+	if err == nil || !strings.Contains(err.Error(), "99") {
+		t.Errorf("versão mais nova deveria ser recusada, veio %v", err)
+	}
+	if c == nil || c.QueueWorkers != defaultQueueWorkers {
+		t.Errorf("deveria retornar os padrões junto com o erro")
+	}
+
+	if _, err := Load(writeConfig(t, `{"transcode": {"crf": 80}}`)); err == nil {
+		t.Errorf("crf fora do intervalo deveria falhar")
+	}
+	t.Setenv(transcodeCRFEnv, "0")
+	if c, _ := Load(writeConfig(t, `{"transcode": {"crf": 0}}`)); c.Transcode.CRF != defaultTranscodeCRF {
+		t.Errorf("crf 0 deveria usar o padrão, veio %d", c.Transcode.CRF)
+	}
+}
+
+func TestSaveRoundTrip(t *testing.T) {
+	clearEnv(t)
+	path := filepath.Join(t.TempDir(), "sub", "config.json")
+	c, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := c.File()
+	s.PreferredLanguages = []string{"pt-BR", "en"}
+	s.Transcode.CRF = 20
+	if err := c.Save(s); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if c.Transcode.CRF != 20 {
+		t.Errorf("Save deveria atualizar os valores efetivos")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"version": 1`) {
+		t.Errorf("arquivo salvo sem versão:\n%s", data)
+	}
+
+	reloaded, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := reloaded.PreferredLanguages; len(got) != 2 || got[0] != "pt-BR" {
+		t.Errorf("idiomas preferidos não persistidos: %v", got)
+	}
+
+	s.YtDlpChannel = "beta"
+	if err := c.Save(s); err == nil {
+		t.Errorf("Save deveria validar antes de gravar")
+	}
+}
+
+func TestValidateCookiesBrowser(t *testing.T) {
+	for _, v := range []string{"firefox", "chrome:Profile 1", "chromium+gnomekeyring"} {
+		if err := (Settings{CookiesFromBrowser: v}).Validate(); err != nil {
+			t.Errorf("%q deveria ser aceito: %v", v, err)
+		}
+	}
+	if err := (Settings{CookiesFromBrowser: "netscape"}).Validate(); err == nil {
+		t.Errorf("navegador desconhecido deveria ser recusado")
+	}
+}
+
+func TestPlatformCookies(t *testing.T) {
+	clearEnv(t)
+	path := writeConfig(t, `{
+		"version": 1,
+		"cookies_from_browser": "firefox",
+		"cookies": {
+			"facebook": {"file": "/tmp/fb-cookies.txt"},
+			"youtube": {}
+		}
+	}`)
+
+	c, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if got := c.PlatformCookies("instagram"); got.Browser != "firefox" || got.File != "" {
+		t.Errorf("plataforma sem ajuste deveria usar os cookies globais: %+v", got)
+	}
+	if got := c.PlatformCookies("facebook"); got.File != "/tmp/fb-cookies.txt" || got.Browser != "" {
+		t.Errorf("cookies do Facebook = %+v", got)
+	}
+	if got := c.PlatformCookies("youtube"); got.Enabled() {
+		t.Errorf("entrada vazia deveria desativar os cookies: %+v", got)
+	}
+
+	t.Setenv(cookiesFileEnv, "/tmp/env-cookies.txt")
+	c, _ = Load(path)
+	if got := c.PlatformCookies("instagram"); got.File != "/tmp/env-cookies.txt" {
+		t.Errorf("DT_COOKIES_FILE deveria valer para as plataformas sem ajuste: %+v", got)
+	}
+
+	bad := Settings{Cookies: map[string]CookieSettings{"tiktok": {Browser: "chrome"}}}
+	if err := bad.Validate(); err == nil {
+		t.Errorf("plataforma desconhecida deveria ser recusada")
+	}
+	bad = Settings{Cookies: map[string]CookieSettings{"instagram": {Browser: "netscape"}}}
+	if err := bad.Validate(); err == nil {
+		t.Errorf("navegador desconhecido deveria ser recusado")
+	}
+}
+
+func TestPlatformProxy(t *testing.T) {
+	clearEnv(t)
+	path := writeConfig(t, `{
+		"version": 1,
+		"proxy": "http://proxy.local:3128",
+		"proxies": {"youtube": "socks5://127.0.0.1:1080", "facebook": ""}
+	}`)
+
+	c, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if got := c.PlatformProxy("youtube"); got != "socks5://127.0.0.1:1080" {
+		t.Errorf("proxy do YouTube = %q", got)
+	}
+	if got := c.PlatformProxy("facebook"); got != "" {
+		t.Errorf("entrada vazia deveria desativar o proxy, veio %q", got)
+	}
+	if got := c.PlatformProxy("instagram"); got != "http://proxy.local:3128" {
+		t.Errorf("plataforma sem ajuste deveria usar o proxy global, veio %q", got)
+	}
+
+	if err := (Settings{Proxy: "proxy.local:3128"}).Validate(); err == nil {
+		t.Errorf("proxy sem esquema deveria ser recusado")
+	}
+	t.Setenv(proxyEnv, "ftp://proxy.local")
+	c, _ = Load(path)
+	if c.Proxy != "http://proxy.local:3128" || c.OverriddenBy("proxy") != "" {
+		t.Errorf("DT_PROXY inválido deveria ser ignorado: %q", c.Proxy)
+	}
+}
+
+func TestJobRateLimit(t *testing.T) {
+	s := Settings{RateLimit: "4M"}
+	if got := s.JobRateLimit(1); got != 4<<20 {
+		t.Errorf("um download deveria usar o limite inteiro, veio %d", got)
+	}
+	if got := s.JobRateLimit(2); got != 2<<20 {
+		t.Errorf("dois downloads deveriam dividir o limite, veio %d", got)
+	}
+	s.RateLimitPerJob = "1M"
+	if got := s.JobRateLimit(2); got != 1<<20 {
+		t.Errorf("o limite por download deveria valer quando menor, veio %d", got)
+	}
+	if got := (Settings{RateLimitPerJob: "500K"}).JobRateLimit(3); got != 500<<10 {
+		t.Errorf("sem limite total, vale o por download, veio %d", got)
+	}
+	if err := (Settings{RateLimit: "rápido"}).Validate(); err == nil {
+		t.Errorf("limite inválido deveria ser recusado")
+	}
+}
+
+func TestCheckDownloadDir(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "nova")
+	if err := CheckDownloadDir(dir); err != nil {
+		t.Fatalf("pasta gravável deveria ser aceita: %v", err)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("arquivo de teste não foi removido: %v", entries)
+	}
+	if err := CheckDownloadDir("relativa"); err == nil {
+		t.Errorf("caminho relativo deveria ser recusado")
+	}
+}
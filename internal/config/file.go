@@ -0,0 +1,195 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/diogocardoso/DownloaderTube/internal/downloader"
+	"github.com/diogocardoso/DownloaderTube/internal/i18n"
+	"github.com/diogocardoso/DownloaderTube/pkg/validator"
+)
+
+// fileVersion é a versão atual do formato do arquivo. Arquivos sem versão são
+// tratados como versão 1; versões mais novas que esta são recusadas para não
+// perder campos ao salvar.
+const fileVersion = 1
+
+type fileFormat struct {
+	Version int `json:"version"`
+	Settings
+}
+
+// CookieBrowsers são os navegadores aceitos pelo --cookies-from-browser do yt-dlp.
+var CookieBrowsers = []string{"brave", "chrome", "chromium", "edge", "firefox", "opera", "safari", "vivaldi", "whale"}
+
+// TranscodePresets são os valores aceitos para o -preset do libx264.
+var TranscodePresets = []string{"ultrafast", "superfast", "veryfast", "faster", "fast", "medium", "slow", "slower", "veryslow"}
+
+// DefaultPath retorna o caminho do arquivo de configuração: DT_CONFIG, se
+// definido, ou config.json no diretório de configuração do usuário.
+func DefaultPath() (string, error) {
+	if p := envString(configPathEnv); p != "" {
+		return p, nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", i18n.LazyErrorf("não foi possível determinar diretório de configuração: %w", err)
+	}
+	return filepath.Join(dir, "DownloaderTube", "config.json"), nil
+}
+
+// Load lê o arquivo de configuração e aplica as variáveis de ambiente. Um
+// arquivo inexistente não é erro. Se o arquivo for inválido, o erro é
+// retornado junto com uma configuração utilizável (padrões + ambiente).
+//
+// As mensagens de erro são traduzidas só ao exibir, pois o idioma da interface
+// é definido a partir da configuração carregada aqui.
+func Load(path string) (*Config, error) {
+	c := New()
+	c.path = path
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return c, i18n.LazyErrorf("erro ao ler configuração %s: %w", path, err)
+	}
+
+	var f fileFormat
+	if err := json.Unmarshal(data, &f); err != nil {
+		return c, i18n.LazyErrorf("erro ao parsear configuração %s: %w", path, err)
+	}
+	if f.Version > fileVersion {
+		return c, i18n.LazyErrorf("configuração %s é da versão %d; esta versão do programa suporta até a %d", path, f.Version, fileVersion)
+	}
+	if err := f.Settings.Validate(); err != nil {
+		return c, i18n.LazyErrorf("configuração %s inválida: %w", path, err)
+	}
+
+	c.apply(f.Settings)
+	return c, nil
+}
+
+// Save valida e grava s no arquivo de configuração e recalcula os valores
+// efetivos (as variáveis de ambiente continuam tendo precedência).
+func (c *Config) Save(s Settings) error {
+	if err := s.Validate(); err != nil {
+		return err
+	}
+	if c.path == "" {
+		return i18n.LazyErrorf("caminho do arquivo de configuração desconhecido")
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o755); err != nil {
+		return i18n.LazyErrorf("erro ao criar diretório da configuração: %w", err)
+	}
+
+	data, err := json.MarshalIndent(fileFormat{Version: fileVersion, Settings: s}, "", "  ")
+	if err != nil {
+		return i18n.LazyErrorf("erro ao serializar configuração: %w", err)
+	}
+
+	tmpPath := c.path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0o644); err != nil {
+		return i18n.LazyErrorf("erro ao gravar configuração: %w", err)
+	}
+	if err := os.Rename(tmpPath, c.path); err != nil {
+		os.Remove(tmpPath)
+		return i18n.LazyErrorf("erro ao salvar configuração: %w", err)
+	}
+
+	c.apply(s)
+	return nil
+}
+
+// Validate confere os valores definidos; campos zerados são aceitos e usam o padrão.
+func (s Settings) Validate() error {
+	if s.Language != "" {
+		if _, ok := i18n.Parse(s.Language); !ok {
+			return i18n.LazyErrorf("language: idioma %q não suportado", s.Language)
+		}
+	}
+	if s.DefaultQuality < 0 {
+		return i18n.LazyErrorf("default_quality: deve ser 0 (melhor disponível) ou uma altura como 720")
+	}
+	for platform, name := range s.QualityPresets {
+		if !slices.Contains(validator.Platforms, validator.Platform(platform)) {
+			return i18n.LazyErrorf("quality_presets: plataforma %q desconhecida", platform)
+		}
+		if _, ok := downloader.ParseQualityPreset(name); !ok {
+			return i18n.LazyErrorf("quality_presets.%s: preset %q desconhecido", platform, name)
+		}
+	}
+	for _, l := range s.PreferredLanguages {
+		if strings.TrimSpace(l) == "" || strings.ContainsAny(l, " ,;") {
+			return i18n.LazyErrorf("preferred_languages: código de idioma inválido %q", l)
+		}
+	}
+	if s.CookiesFromBrowser != "" && !IsCookieBrowser(s.CookiesFromBrowser) {
+		return i18n.LazyErrorf("cookies_from_browser: use um de %s", strings.Join(CookieBrowsers, ", "))
+	}
+	for platform, cs := range s.Cookies {
+		if !slices.Contains(validator.Platforms, validator.Platform(platform)) {
+			return i18n.LazyErrorf("cookies: plataforma %q desconhecida", platform)
+		}
+		if cs.Browser != "" && !IsCookieBrowser(cs.Browser) {
+			return i18n.LazyErrorf("cookies.%s.browser: use um de %s", platform, strings.Join(CookieBrowsers, ", "))
+		}
+	}
+	if s.Proxy != "" {
+		if _, err := downloader.ParseProxy(s.Proxy); err != nil {
+			return i18n.LazyErrorf("proxy: %w", err)
+		}
+	}
+	for platform, proxy := range s.Proxies {
+		if !slices.Contains(validator.Platforms, validator.Platform(platform)) {
+			return i18n.LazyErrorf("proxies: plataforma %q desconhecida", platform)
+		}
+		if proxy == "" {
+			continue
+		}
+		if _, err := downloader.ParseProxy(proxy); err != nil {
+			return i18n.LazyErrorf("proxies.%s: %w", platform, err)
+		}
+	}
+	if s.YtDlpChannel != "" && s.YtDlpChannel != YtDlpChannelNightly && s.YtDlpChannel != YtDlpChannelStable {
+		return i18n.LazyErrorf("ytdlp_channel: use %q ou %q", YtDlpChannelNightly, YtDlpChannelStable)
+	}
+	if s.QueueWorkers < 0 {
+		return i18n.LazyErrorf("queue_workers: não pode ser negativo")
+	}
+	if _, err := downloader.ParseRate(s.RateLimit); err != nil {
+		return i18n.LazyErrorf("rate_limit: %w", err)
+	}
+	if _, err := downloader.ParseRate(s.RateLimitPerJob); err != nil {
+		return i18n.LazyErrorf("rate_limit_per_job: %w", err)
+	}
+	if s.Transcode.Workers < 0 {
+		return i18n.LazyErrorf("transcode.workers: não pode ser negativo")
+	}
+	if s.Transcode.Preset != "" && !validPreset(s.Transcode.Preset) {
+		return i18n.LazyErrorf("transcode.preset: use um de %s", strings.Join(TranscodePresets, ", "))
+	}
+	if s.Transcode.CRF < 0 || s.Transcode.CRF > 51 {
+		return i18n.LazyErrorf("transcode.crf: deve estar entre 1 e 51 (0 ou ausente usa o padrão)")
+	}
+	return nil
+}
+
+// IsCookieBrowser aceita o formato do yt-dlp NAVEGADOR[+CHAVEIRO][:PERFIL],
+// conferindo só o nome do navegador.
+func IsCookieBrowser(v string) bool {
+	name := strings.ToLower(v)
+	if i := strings.IndexAny(name, "+:"); i >= 0 {
+		name = name[:i]
+	}
+	return slices.Contains(CookieBrowsers, name)
+}
+
+func validPreset(p string) bool {
+	return slices.Contains(TranscodePresets, p)
+}
@@ -2,19 +2,23 @@ package deps
 
 import (
 	"archive/zip"
+	"context"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"runtime"
 	"strings"
 	"time"
+
+	"github.com/diogocardoso/DownloaderTube/internal/downloader"
+	"github.com/diogocardoso/DownloaderTube/internal/i18n"
 )
 
 const (
-	ytDlpChannelEnv     = "DT_YTDLP_CHANNEL"
 	ytDlpChannelStable  = "stable"
 	ytDlpChannelNightly = "nightly"
 	ytDlpDefaultChannel = ytDlpChannelNightly
@@ -23,7 +27,7 @@ const (
 func getBinDir() (string, error) {
 	cacheDir, err := os.UserCacheDir()
 	if err != nil {
-		return "", fmt.Errorf("não foi possível determinar diretório de cache: %w", err)
+		return "", i18n.Errorf("não foi possível determinar diretório de cache: %w", err)
 	}
 	return filepath.Join(cacheDir, "DownloaderTube", "bin"), nil
 }
@@ -41,76 +45,78 @@ func binaryName(base string) string {
 }
 
 // EnsureDependencies verifica se yt-dlp e ffmpeg estão disponíveis.
-// Se não encontrados, baixa automaticamente do GitHub.
-func EnsureDependencies() error {
+// Se não encontrados, baixa automaticamente do GitHub. channel escolhe o
+// canal do yt-dlp gerenciado ("nightly" ou "stable"; vazio usa o padrão).
+func EnsureDependencies(channel string) error {
+	channel = ytDlpChannel(channel)
+
 	binDir, err := getBinDir()
 	if err != nil {
 		return err
 	}
 
 	if err := os.MkdirAll(binDir, 0755); err != nil {
-		return fmt.Errorf("erro ao criar diretório de dependências: %w", err)
+		return i18n.Errorf("erro ao criar diretório de dependências: %w", err)
 	}
 
 	currentPath := os.Getenv("PATH")
 	os.Setenv("PATH", binDir+string(os.PathListSeparator)+currentPath)
 
-	needYtDlp := shouldInstallManagedYtDlp(binDir)
+	needYtDlp := shouldInstallManagedYtDlp(binDir, channel)
 	needFfmpeg := !isAvailable("ffmpeg")
 
 	if !needYtDlp && !needFfmpeg {
 		return nil
 	}
 
-	fmt.Println()
-	fmt.Println(" Dependências necessárias não encontradas.")
-	fmt.Println(" Iniciando download automático...")
-	fmt.Printf(" Local: %s\n", binDir)
-	fmt.Println(" -------------------------------")
+	fmt.Fprintln(out)
+	i18n.Fprintln(out, " Dependências necessárias não encontradas.")
+	i18n.Fprintln(out, " Iniciando download automático...")
+	i18n.Fprintf(out, " Local: %s\n", binDir)
+	fmt.Fprintln(out, " -------------------------------")
 
 	if needYtDlp {
-		fmt.Println()
-		fmt.Println(" yt-dlp: baixando...")
-		if err := installYtDlp(binDir); err != nil {
-			return fmt.Errorf("falha ao instalar yt-dlp: %w", err)
+		fmt.Fprintln(out)
+		i18n.Fprintln(out, " yt-dlp: baixando...")
+		if err := installYtDlp(binDir, channel); err != nil {
+			return i18n.Errorf("falha ao instalar yt-dlp: %w", err)
 		}
-		fmt.Println(" yt-dlp: instalado com sucesso!")
+		i18n.Fprintln(out, " yt-dlp: instalado com sucesso!")
 	}
 
 	if needFfmpeg {
-		fmt.Println()
-		fmt.Println(" ffmpeg: baixando (pode demorar alguns minutos)...")
+		fmt.Fprintln(out)
+		i18n.Fprintln(out, " ffmpeg: baixando (pode demorar alguns minutos)...")
 		if err := installFfmpeg(binDir); err != nil {
-			return fmt.Errorf("falha ao instalar ffmpeg: %w", err)
+			return i18n.Errorf("falha ao instalar ffmpeg: %w", err)
 		}
-		fmt.Println(" ffmpeg: instalado com sucesso!")
+		i18n.Fprintln(out, " ffmpeg: instalado com sucesso!")
 	}
 
-	fmt.Println()
-	fmt.Println(" -------------------------------")
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, " -------------------------------")
 
 	if err := verifyDependencies(); err != nil {
 		return err
 	}
 
-	fmt.Println(" Todas as dependências estão prontas!")
-	fmt.Println()
+	i18n.Fprintln(out, " Todas as dependências estão prontas!")
+	fmt.Fprintln(out)
 
 	return nil
 }
 
 func verifyDependencies() error {
 	if !isAvailable("yt-dlp") {
-		return fmt.Errorf("yt-dlp não está acessível após instalação. Verifique permissões")
+		return i18n.Errorf("yt-dlp não está acessível após instalação. Verifique permissões")
 	}
 	if !isAvailable("ffmpeg") {
-		return fmt.Errorf("ffmpeg não está acessível após instalação. Verifique permissões")
+		return i18n.Errorf("ffmpeg não está acessível após instalação. Verifique permissões")
 	}
 	return nil
 }
 
-func installYtDlp(binDir string) error {
-	channel := ytDlpChannel()
+func installYtDlp(binDir, channel string) error {
 	var url string
 	if channel == ytDlpChannelNightly {
 		switch runtime.GOOS {
@@ -121,7 +127,7 @@ func installYtDlp(binDir string) error {
 		case "darwin":
 			url = "https://github.com/yt-dlp/yt-dlp-nightly-builds/releases/latest/download/yt-dlp_macos"
 		default:
-			return fmt.Errorf("plataforma %s não suportada para auto-download do yt-dlp", runtime.GOOS)
+			return i18n.Errorf("plataforma %s não suportada para auto-download do yt-dlp", runtime.GOOS)
 		}
 	} else {
 		switch runtime.GOOS {
@@ -132,7 +138,7 @@ func installYtDlp(binDir string) error {
 		case "darwin":
 			url = "https://github.com/yt-dlp/yt-dlp/releases/latest/download/yt-dlp_macos"
 		default:
-			return fmt.Errorf("plataforma %s não suportada para auto-download do yt-dlp", runtime.GOOS)
+			return i18n.Errorf("plataforma %s não suportada para auto-download do yt-dlp", runtime.GOOS)
 		}
 	}
 
@@ -140,9 +146,8 @@ func installYtDlp(binDir string) error {
 	return downloadFile(url, destPath, "yt-dlp")
 }
 
-func ytDlpChannel() string {
-	channel := strings.ToLower(strings.TrimSpace(os.Getenv(ytDlpChannelEnv)))
-	switch channel {
+func ytDlpChannel(raw string) string {
+	switch strings.ToLower(strings.TrimSpace(raw)) {
 	case "", ytDlpChannelNightly:
 		return ytDlpChannelNightly
 	case ytDlpChannelStable:
@@ -152,7 +157,7 @@ func ytDlpChannel() string {
 	}
 }
 
-func shouldInstallManagedYtDlp(binDir string) bool {
+func shouldInstallManagedYtDlp(binDir, channel string) bool {
 	managedPath := filepath.Join(binDir, binaryName("yt-dlp"))
 	if _, err := os.Stat(managedPath); err != nil {
 		return true
@@ -161,7 +166,6 @@ func shouldInstallManagedYtDlp(binDir string) bool {
 	if err != nil {
 		return true
 	}
-	channel := ytDlpChannel()
 	if channel == ytDlpChannelNightly && !strings.Contains(version, "nightly@") {
 		return true
 	}
@@ -187,7 +191,7 @@ func installFfmpeg(binDir string) error {
 	case "linux":
 		return installFfmpegLinux(binDir)
 	default:
-		return fmt.Errorf("instale ffmpeg manualmente: https://ffmpeg.org/download.html")
+		return i18n.Errorf("instale ffmpeg manualmente: https://ffmpeg.org/download.html")
 	}
 }
 
@@ -200,7 +204,7 @@ func installFfmpegWindows(binDir string) error {
 	}
 	defer os.Remove(zipPath)
 
-	fmt.Println(" Extraindo ffmpeg...")
+	i18n.Fprintln(out, " Extraindo ffmpeg...")
 	return extractFromZip(zipPath, binDir, []string{"ffmpeg.exe", "ffprobe.exe"})
 }
 
@@ -213,32 +217,106 @@ func installFfmpegLinux(binDir string) error {
 	}
 	defer os.Remove(tarPath)
 
-	fmt.Println(" Extraindo ffmpeg...")
+	i18n.Fprintln(out, " Extraindo ffmpeg...")
 	return extractFromTarXz(tarPath, binDir)
 }
 
-var httpClient = &http.Client{
-	Timeout: 10 * time.Minute,
+// out recebe as mensagens da instalação. É o stderr, para não se misturar com
+// o stdout do modo não interativo (o caminho do arquivo ou as linhas JSON).
+var out io.Writer = os.Stderr
+
+// O prazo dos downloads é controlado em downloadFile: downloadTimeout para o
+// arquivo inteiro ou, com limite de velocidade, stallTimeout sem receber dados.
+const (
+	downloadTimeout = 10 * time.Minute
+	stallTimeout    = 2 * time.Minute
+)
+
+var httpClient = &http.Client{}
+
+// SetProxy faz o download das dependências passar pelo proxy (http, https ou
+// socks5), já validado pela configuração. Vazio usa a conexão padrão, que
+// respeita HTTP_PROXY/HTTPS_PROXY.
+func SetProxy(raw string) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		httpClient.Transport = nil
+		return
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = http.ProxyURL(u)
+	httpClient.Transport = transport
+}
+
+// rateLimit é a velocidade máxima do download das dependências, em bytes por
+// segundo; 0 = sem limite.
+var rateLimit int64
+
+// SetRateLimit limita a velocidade do download das dependências. Com limite, o
+// tempo total depende da velocidade, então o prazo passa a valer para cada
+// pausa sem dados (ver stallTimeout).
+func SetRateLimit(bytesPerSecond int64) {
+	rateLimit = max(bytesPerSecond, 0)
+}
+
+// rateLimitedReader segura a leitura para que a média desde o início não passe
+// de rate bytes por segundo. Cada leitura com dados adia o deadline por
+// stallTimeout, para que só um download parado seja interrompido.
+type rateLimitedReader struct {
+	r        io.Reader
+	rate     int64
+	deadline *time.Timer
+	start    time.Time
+	read     int64
+}
+
+func (l *rateLimitedReader) Read(p []byte) (int, error) {
+	if l.start.IsZero() {
+		l.start = time.Now()
+	}
+	// Lê no máximo um décimo de segundo de dados por vez, para a espera ser suave.
+	if chunk := int(l.rate / 10); chunk > 0 && len(p) > chunk {
+		p = p[:chunk]
+	}
+	n, err := l.r.Read(p)
+	l.read += int64(n)
+	if n > 0 && l.deadline != nil {
+		l.deadline.Reset(stallTimeout)
+	}
+	due := time.Duration(float64(l.read) / float64(l.rate) * float64(time.Second))
+	if wait := due - time.Since(l.start); wait > 0 {
+		time.Sleep(wait)
+	}
+	return n, err
 }
 
 // downloadFile baixa um arquivo de url para destPath, exibindo barra de progresso.
 func downloadFile(rawURL, destPath, label string) error {
-	fmt.Printf(" URL: %s\n", rawURL)
+	fmt.Fprintf(out, " URL: %s\n", rawURL)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	deadline := time.AfterFunc(downloadTimeout, cancel)
+	defer deadline.Stop()
 
-	req, err := http.NewRequest(http.MethodGet, rawURL, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
 	if err != nil {
-		return fmt.Errorf("erro ao criar request para %s: %w", rawURL, err)
+		return i18n.Errorf("erro ao criar request para %s: %w", rawURL, err)
 	}
 	req.Header.Set("User-Agent", "DownloaderTube/1.0 (https://webadvance.com.br)")
 
 	resp, err := httpClient.Do(req)
 	if err != nil {
-		return fmt.Errorf("erro na conexão com %s: %w", rawURL, err)
+		return i18n.Errorf("erro na conexão com %s: %w", rawURL, err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("download de %s falhou com status HTTP %d", label, resp.StatusCode)
+		return i18n.Errorf("download de %s falhou com status HTTP %d", label, resp.StatusCode)
 	}
 
 	tmpPath := destPath + ".download"
@@ -247,47 +325,52 @@ func downloadFile(rawURL, destPath, label string) error {
 
 	file, err := os.Create(tmpPath)
 	if err != nil {
-		return fmt.Errorf("erro ao criar arquivo temporário %s: %w", tmpPath, err)
+		return i18n.Errorf("erro ao criar arquivo temporário %s: %w", tmpPath, err)
 	}
 
 	pw := &progressWriter{
 		total: resp.ContentLength,
 		label: label,
+		limit: rateLimit,
 	}
 
-	_, copyErr := io.Copy(file, io.TeeReader(resp.Body, pw))
+	var body io.Reader = resp.Body
+	if rateLimit > 0 {
+		body = &rateLimitedReader{r: body, rate: rateLimit, deadline: deadline}
+	}
+	_, copyErr := io.Copy(file, io.TeeReader(body, pw))
 	file.Close()
 
 	if copyErr != nil {
 		os.Remove(tmpPath)
-		return fmt.Errorf("erro durante download de %s: %w", label, copyErr)
+		return i18n.Errorf("erro durante download de %s: %w", label, copyErr)
 	}
 
-	fmt.Println()
+	fmt.Fprintln(out)
 
 	info, statErr := os.Stat(tmpPath)
 	if statErr != nil || info.Size() == 0 {
 		os.Remove(tmpPath)
-		return fmt.Errorf("arquivo baixado de %s está vazio ou corrompido", label)
+		return i18n.Errorf("arquivo baixado de %s está vazio ou corrompido", label)
 	}
 
 	if err := os.Rename(tmpPath, destPath); err != nil {
 		os.Remove(tmpPath)
-		return fmt.Errorf("erro ao salvar arquivo %s: %w", destPath, err)
+		return i18n.Errorf("erro ao salvar arquivo %s: %w", destPath, err)
 	}
 
 	if runtime.GOOS != "windows" {
 		os.Chmod(destPath, 0755)
 	}
 
-	fmt.Printf(" Salvo: %s (%.1fMB)\n", destPath, float64(info.Size())/1024/1024)
+	i18n.Fprintf(out, " Salvo: %s (%.1fMB)\n", destPath, float64(info.Size())/1024/1024)
 	return nil
 }
 
 func extractFromZip(zipPath, destDir string, targets []string) error {
 	r, err := zip.OpenReader(zipPath)
 	if err != nil {
-		return fmt.Errorf("erro ao abrir zip: %w", err)
+		return i18n.Errorf("erro ao abrir zip: %w", err)
 	}
 	defer r.Close()
 
@@ -308,13 +391,13 @@ func extractFromZip(zipPath, destDir string, targets []string) error {
 
 		destPath := filepath.Join(destDir, filepath.Base(f.Name))
 		if err := extractZipEntry(f, destPath); err != nil {
-			return fmt.Errorf("erro ao extrair %s: %w", base, err)
+			return i18n.Errorf("erro ao extrair %s: %w", base, err)
 		}
 		extracted++
 	}
 
 	if extracted == 0 {
-		return fmt.Errorf("arquivos alvo não encontrados dentro do zip")
+		return i18n.Errorf("arquivos alvo não encontrados dentro do zip")
 	}
 
 	return nil
@@ -344,7 +427,7 @@ func extractFromTarXz(tarPath, binDir string) error {
 
 	cmd := exec.Command("tar", "xf", tarPath, "-C", tmpDir)
 	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("erro ao extrair tar.xz (tar está instalado?): %w", err)
+		return i18n.Errorf("erro ao extrair tar.xz (tar está instalado?): %w", err)
 	}
 
 	targets := []string{"ffmpeg", "ffprobe"}
@@ -367,7 +450,7 @@ func extractFromTarXz(tarPath, binDir string) error {
 	})
 
 	if found == 0 {
-		return fmt.Errorf("ffmpeg não encontrado no arquivo extraído")
+		return i18n.Errorf("ffmpeg não encontrado no arquivo extraído")
 	}
 
 	return nil
@@ -397,6 +480,8 @@ type progressWriter struct {
 	total      int64
 	downloaded int64
 	label      string
+	// limit é o limite de velocidade ativo, exibido ao lado do progresso.
+	limit int64
 }
 
 func (pw *progressWriter) Write(p []byte) (int, error) {
@@ -408,7 +493,7 @@ func (pw *progressWriter) Write(p []byte) (int, error) {
 
 func (pw *progressWriter) printProgress() {
 	if pw.total <= 0 {
-		fmt.Printf("\r Baixando %s... %.1fMB", pw.label, float64(pw.downloaded)/1024/1024)
+		i18n.Fprintf(out, "\r Baixando %s... %.1fMB%s", pw.label, float64(pw.downloaded)/1024/1024, pw.limitNote())
 		return
 	}
 
@@ -423,5 +508,12 @@ func (pw *progressWriter) printProgress() {
 	currentMB := float64(pw.downloaded) / 1024 / 1024
 	totalMB := float64(pw.total) / 1024 / 1024
 
-	fmt.Printf("\r [%s] %.0f%% - %.1fMB/%.1fMB", bar, pct, currentMB, totalMB)
+	fmt.Fprintf(out, "\r [%s] %.0f%% - %.1fMB/%.1fMB%s", bar, pct, currentMB, totalMB, pw.limitNote())
+}
+
+func (pw *progressWriter) limitNote() string {
+	if pw.limit <= 0 {
+		return ""
+	}
+	return i18n.Sprintf(" (limite %s)", downloader.FormatRate(pw.limit))
 }
@@ -0,0 +1,124 @@
+package downloader
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// AudioFormat é o formato do arquivo gerado no modo somente áudio.
+type AudioFormat string
+
+const (
+	AudioMP3  AudioFormat = "mp3"
+	AudioM4A  AudioFormat = "m4a"
+	AudioOpus AudioFormat = "opus"
+)
+
+// AudioFormats lista os formatos na ordem exibida nos menus.
+var AudioFormats = []AudioFormat{AudioMP3, AudioM4A, AudioOpus}
+
+// ParseAudioFormat reconhece o nome de um formato de áudio, sem diferenciar maiúsculas.
+func ParseAudioFormat(name string) (AudioFormat, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for _, f := range AudioFormats {
+		if string(f) == name {
+			return f, true
+		}
+	}
+	return "", false
+}
+
+// Label retorna o nome do formato para exibição.
+func (f AudioFormat) Label() string {
+	switch f {
+	case AudioMP3:
+		return "MP3"
+	case AudioM4A:
+		return "M4A (AAC)"
+	case AudioOpus:
+		return "Opus"
+	default:
+		return string(f)
+	}
+}
+
+// AudioOptions configura o modo somente áudio.
+type AudioOptions struct {
+	Format AudioFormat `json:"format"`
+	// Bitrate é a taxa máxima (kbps) da faixa de origem; 0 = melhor disponível.
+	Bitrate int `json:"bitrate,omitempty"`
+}
+
+// Label descreve as opções para exibição (ex: "MP3 128 kbps").
+func (o AudioOptions) Label() string {
+	if o.Bitrate > 0 {
+		return fmt.Sprintf("%s %d kbps", o.Format.Label(), o.Bitrate)
+	}
+	return o.Format.Label()
+}
+
+// collectAudioTracks lista as faixas só de áudio, uma por taxa de bits, da
+// menor para a maior.
+func collectAudioTracks(formats []ytdlpFormat) []AudioTrack {
+	seen := make(map[int]bool)
+	var tracks []AudioTrack
+	for _, f := range formats {
+		if f.ACodec == "none" || f.ABR <= 0 || (f.VCodec != "none" && f.Height > 0) {
+			continue
+		}
+		kbps := int(math.Round(f.ABR))
+		if seen[kbps] {
+			continue
+		}
+		seen[kbps] = true
+
+		codec := strings.SplitN(f.ACodec, ".", 2)[0]
+		label := fmt.Sprintf("%d kbps", kbps)
+		if codec != "" {
+			label += " (" + codec + ")"
+		}
+		tracks = append(tracks, AudioTrack{Bitrate: kbps, Codec: codec, Label: label})
+	}
+
+	sort.Slice(tracks, func(i, j int) bool {
+		return tracks[i].Bitrate < tracks[j].Bitrate
+	})
+	return tracks
+}
+
+// buildAudioFormatString escolhe a melhor faixa de áudio até bitrate (0 = sem
+// limite), preferindo o idioma pedido. Sem faixa só de áudio, usa o melhor
+// arquivo combinado e extrai o áudio dele.
+func buildAudioFormatString(bitrate int, langCode string) string {
+	limit := ""
+	if bitrate > 0 {
+		limit = fmt.Sprintf("[abr<=%d]", bitrate)
+	}
+
+	var selectors []string
+	if langCode != "" {
+		selectors = append(selectors, "ba"+limit+"[language="+langCode+"]")
+		if base := baseLang(langCode); base != langCode {
+			selectors = append(selectors, "ba"+limit+"[language="+base+"]")
+		}
+	}
+	if limit != "" {
+		selectors = append(selectors, "ba"+limit)
+	}
+	selectors = append(selectors, "ba", "b")
+	return strings.Join(selectors, "/")
+}
+
+// ytdlpAudioArgs são os argumentos de download no modo somente áudio: extrai
+// com o ffmpeg e grava título, autor e thumbnail como tags do arquivo.
+func ytdlpAudioArgs(formatStr, outputTemplate string, format AudioFormat) []string {
+	args := ytdlpDownloadArgs(formatStr, outputTemplate)
+	return append(args,
+		"-x",
+		"--audio-format", string(format),
+		"--audio-quality", "0",
+		"--embed-thumbnail",
+	)
+}
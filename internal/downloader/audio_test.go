@@ -0,0 +1,41 @@
+package downloader
+
+import "testing"
+
+func TestCollectAudioTracks(t *testing.T) {
+	formats := []ytdlpFormat{
+		{VCodec: "none", ACodec: "opus", ABR: 129.5},
+		{VCodec: "none", ACodec: "mp4a.40.2", ABR: 48},
+		{VCodec: "none", ACodec: "opus", ABR: 130},
+		{VCodec: "avc1.64001F", ACodec: "mp4a.40.2", ABR: 96, Height: 720},
+		{VCodec: "vp9", ACodec: "none", Height: 1080},
+	}
+
+	tracks := collectAudioTracks(formats)
+	if len(tracks) != 2 {
+		t.Fatalf("esperava 2 faixas (sem repetir taxa nem incluir vídeo), veio %+v", tracks)
+	}
+	if tracks[0].Bitrate != 48 || tracks[0].Label != "48 kbps (mp4a)" {
+		t.Errorf("primeira faixa = %+v", tracks[0])
+	}
+	if tracks[1].Bitrate != 130 || tracks[1].Codec != "opus" {
+		t.Errorf("segunda faixa = %+v", tracks[1])
+	}
+}
+
+func TestBuildAudioFormatString(t *testing.T) {
+	cases := []struct {
+		bitrate int
+		lang    string
+		want    string
+	}{
+		{0, "", "ba/b"},
+		{128, "", "ba[abr<=128]/ba/b"},
+		{0, "pt-BR", "ba[language=pt-BR]/ba[language=pt]/ba/b"},
+	}
+	for _, c := range cases {
+		if got := buildAudioFormatString(c.bitrate, c.lang); got != c.want {
+			t.Errorf("buildAudioFormatString(%d, %q) = %q, esperava %q", c.bitrate, c.lang, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,319 @@
+package downloader
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/diogocardoso/DownloaderTube/internal/i18n"
+)
+
+// CarouselItem é um item de um carrossel do Instagram.
+type CarouselItem struct {
+	// Index é a posição no post (1 = primeiro).
+	Index int `json:"index"`
+	// Photo indica uma foto; os demais itens são vídeos.
+	Photo    bool   `json:"photo,omitempty"`
+	Duration string `json:"duration,omitempty"`
+
+	imageURL string
+}
+
+// carouselItems classifica as entradas do post em vídeos e fotos; uma entrada
+// sem formato de vídeo é tratada como foto.
+func carouselItems(entries []ytdlpInfo) []CarouselItem {
+	items := make([]CarouselItem, 0, len(entries))
+	for i, e := range entries {
+		item := CarouselItem{Index: i + 1, Duration: e.DurationString}
+		if !hasVideoFormat(e) {
+			item.Photo = true
+			item.Duration = ""
+			item.imageURL = bestImageURL(e)
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+func hasVideoFormat(info ytdlpInfo) bool {
+	for _, f := range info.Formats {
+		if f.VCodec != "none" && f.Height > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// bestImageURL escolhe a maior versão da imagem de uma foto.
+func bestImageURL(info ytdlpInfo) string {
+	best, bestArea := "", -1
+	for _, t := range info.Thumbnails {
+		if t.URL == "" {
+			continue
+		}
+		if area := t.Width * t.Height; area > bestArea {
+			best, bestArea = t.URL, area
+		}
+	}
+	if best == "" {
+		best = info.Thumbnail
+	}
+	return best
+}
+
+// selectCarouselItems filtra os itens pelas posições pedidas, sem repetir
+// posições; vazio mantém todos.
+func selectCarouselItems(items []CarouselItem, wanted []int) ([]CarouselItem, error) {
+	if len(wanted) == 0 {
+		return items, nil
+	}
+	seen := make(map[int]bool)
+	selected := make([]CarouselItem, 0, len(wanted))
+	for _, n := range wanted {
+		if n < 1 || n > len(items) {
+			return nil, i18n.Errorf("o carrossel não tem o item %d", n)
+		}
+		if seen[n] {
+			continue
+		}
+		seen[n] = true
+		selected = append(selected, items[n-1])
+	}
+	return selected, nil
+}
+
+// instagramShortcode extrai o código do post de URLs /p/<código>/, inclusive
+// na forma /<usuário>/p/<código>/. Retorna "" para outras URLs.
+func instagramShortcode(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	for i := 0; i+1 < len(segments); i++ {
+		if segments[i] == "p" {
+			return sanitizeID(segments[i+1])
+		}
+	}
+	return ""
+}
+
+// carouselItemID é o ID usado no nome do arquivo: instagram_<shortcode>_<n>.
+func carouselItemID(shortcode string, index int) string {
+	return shortcode + "_" + strconv.Itoa(index)
+}
+
+// downloadCarousel baixa os itens escolhidos de um carrossel: os vídeos em uma
+// execução do yt-dlp, com o mesmo acabamento de um vídeo avulso, e as fotos
+// direto da CDN. No modo somente áudio as fotos são ignoradas. Itens que
+// falham viram aviso; só é erro quando nenhum arquivo foi gerado.
+func (id *InstagramDownloader) downloadCarousel(ctx context.Context, req DownloadRequest, info ytdlpPlaylistInfo, progress ProgressFunc) (DownloadResult, error) {
+	shortcode := instagramShortcode(req.URL)
+	if shortcode == "" {
+		shortcode = sanitizeID(info.ID)
+	}
+	selected, err := selectCarouselItems(carouselItems(info.Entries), req.Items)
+	if err != nil {
+		return DownloadResult{}, err
+	}
+
+	var videos, photos []CarouselItem
+	for _, it := range selected {
+		switch {
+		case !it.Photo:
+			videos = append(videos, it)
+		case req.Audio == nil:
+			photos = append(photos, it)
+		}
+	}
+	if len(videos) == 0 && len(photos) == 0 {
+		return DownloadResult{}, i18n.Errorf("nenhum item do carrossel para baixar")
+	}
+	debugLogf("[instagram] carousel shortcode=%s videos=%d photos=%d", shortcode, len(videos), len(photos))
+
+	files := make(map[int]string, len(selected))
+	var failures, compat []string
+	cleanup := func() {
+		for _, f := range files {
+			removePartialFiles([]string{f})
+		}
+	}
+
+	if len(videos) > 0 {
+		results, err := id.downloadCarouselVideos(ctx, req, shortcode, videos, progress)
+		if ctx.Err() != nil {
+			cleanup()
+			return DownloadResult{}, canceledError(ctx)
+		}
+		for n, res := range results {
+			files[n] = res.FilePath
+			if res.CompatibilityWarning != "" {
+				compat = append(compat, i18n.Sprintf("item %d: %s", n, res.CompatibilityWarning))
+			}
+		}
+		if err != nil {
+			failures = append(failures, err.Error())
+		}
+	}
+
+	client := proxyClient(id.Proxy)
+	for i, it := range photos {
+		progress.emit(ProgressEvent{Phase: PhaseDownloadingPhotos, Percent: -1, StreamIndex: i + 1, StreamCount: len(photos)})
+		if it.imageURL == "" {
+			failures = append(failures, i18n.Sprintf("item %d: imagem não encontrada", it.Index))
+			continue
+		}
+		path, err := downloadPhoto(ctx, client, it.imageURL, req.Dest, carouselItemID(shortcode, it.Index), req.FilePrefix)
+		if ctx.Err() != nil {
+			cleanup()
+			return DownloadResult{}, canceledError(ctx)
+		}
+		if err != nil {
+			debugLogf("[instagram] photo %d failed: %v", it.Index, err)
+			failures = append(failures, i18n.Sprintf("item %d: %v", it.Index, err))
+			continue
+		}
+		files[it.Index] = path
+	}
+
+	result := DownloadResult{
+		MediaID:              shortcode,
+		CompatibilityWarning: strings.Join(compat, "; "),
+		ItemWarning:          strings.Join(failures, "; "),
+	}
+	for _, it := range selected {
+		if f, ok := files[it.Index]; ok {
+			result.Files = append(result.Files, f)
+		}
+	}
+	if len(result.Files) == 0 {
+		return DownloadResult{}, i18n.Errorf("nenhum item do carrossel foi baixado: %s", result.ItemWarning)
+	}
+	result.FilePath = result.Files[0]
+	return result, nil
+}
+
+// downloadCarouselVideos baixa os vídeos escolhidos com --playlist-items e
+// devolve o resultado de cada um pela posição no post.
+func (id *InstagramDownloader) downloadCarouselVideos(ctx context.Context, req DownloadRequest, shortcode string, videos []CarouselItem, progress ProgressFunc) (map[int]DownloadResult, error) {
+	positions := make([]string, len(videos))
+	for i, v := range videos {
+		positions[i] = strconv.Itoa(v.Index)
+	}
+
+	// Legendas e capítulos não se aplicam aos vídeos curtos de um carrossel.
+	req.Subtitles = nil
+	req.Chapters = ""
+
+	startedAt := time.Now()
+	args := id.downloadArgs(req)
+	args = append(args, ytdlpIndexPrintArgs...)
+	args = append(args,
+		"--yes-playlist",
+		"--ignore-errors",
+		"--playlist-items", strings.Join(positions, ","),
+		req.URL,
+	)
+	out, err := runYtDlpDownloadWithCookies(ctx, "instagram", args, id.Cookies, progress)
+	if ctx.Err() != nil {
+		return nil, err
+	}
+
+	results := make(map[int]DownloadResult, len(out.Items))
+	for _, it := range out.Items {
+		item := ytdlpOutput{FilePath: it.FilePath, MediaID: carouselItemID(shortcode, it.Index)}
+		res, ferr := finalizeDownload(ctx, "instagram", item, req, startedAt, progress)
+		if ferr != nil {
+			return results, ferr
+		}
+		results[it.Index] = res
+	}
+	if err == nil && len(results) < len(videos) {
+		err = i18n.Errorf("%d de %d vídeo(s) não foram baixados", len(videos)-len(results), len(videos))
+	}
+	return results, err
+}
+
+// downloadPhoto baixa uma foto do carrossel com client para dest como
+// instagram_<id>, com a extensão da URL. Grava em .part e renomeia ao concluir.
+func downloadPhoto(ctx context.Context, client *http.Client, imageURL, dest, id, prefix string) (string, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, imageURL, nil)
+	if err != nil {
+		return "", i18n.Errorf("URL da foto inválida: %w", err)
+	}
+	httpReq.Header.Set("User-Agent", "Mozilla/5.0")
+
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		return "", i18n.Errorf("erro ao baixar foto: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", i18n.Errorf("erro ao baixar foto: HTTP %d", resp.StatusCode)
+	}
+
+	ext := photoExt(imageURL, resp.Header.Get("Content-Type"))
+	path := filepath.Join(dest, id+ext)
+	tmpPath := path + ".part"
+	f, err := os.Create(tmpPath)
+	if err != nil {
+		return "", i18n.Errorf("erro ao criar arquivo: %w", err)
+	}
+	_, err = io.Copy(f, resp.Body)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(tmpPath)
+		return "", i18n.Errorf("erro ao gravar foto: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return "", i18n.Errorf("erro ao gravar foto: %w", err)
+	}
+
+	named, warning := ensurePlatformFileName(path, "instagram", id)
+	if warning != "" {
+		debugLogf("[instagram] photo rename: %s", warning)
+	}
+	named, warning = prefixFileName(named, prefix)
+	if warning != "" {
+		debugLogf("[instagram] photo prefix: %s", warning)
+	}
+	return named, nil
+}
+
+// photoExt deduz a extensão da foto pela URL e, na falta dela, pelo Content-Type.
+func photoExt(imageURL, contentType string) string {
+	if u, err := url.Parse(imageURL); err == nil {
+		switch ext := strings.ToLower(filepath.Ext(u.Path)); ext {
+		case ".jpg", ".jpeg", ".png", ".webp", ".heic":
+			return ext
+		}
+	}
+	switch {
+	case strings.HasPrefix(contentType, "image/webp"):
+		return ".webp"
+	case strings.HasPrefix(contentType, "image/png"):
+		return ".png"
+	case strings.HasPrefix(contentType, "image/heic"):
+		return ".heic"
+	}
+	return ".jpg"
+}
+
+// IsPhotoFile indica se o arquivo é uma foto (ex: item de carrossel), que não
+// passa pela análise de vídeo.
+func IsPhotoFile(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".jpg", ".jpeg", ".png", ".webp", ".heic":
+		return true
+	}
+	return false
+}
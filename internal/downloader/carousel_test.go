@@ -0,0 +1,72 @@
+package downloader
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCarouselItems(t *testing.T) {
+	entries := []ytdlpInfo{
+		{DurationString: "0:15", Formats: []ytdlpFormat{{VCodec: "avc1", Height: 1080}}},
+		{Thumbnails: []ytdlpThumbnail{
+			{URL: "https://cdn/p_320.jpg", Width: 320, Height: 320},
+			{URL: "https://cdn/p_1080.jpg", Width: 1080, Height: 1080},
+		}},
+		{Thumbnail: "https://cdn/only.jpg", Formats: []ytdlpFormat{{VCodec: "none"}}},
+	}
+	items := carouselItems(entries)
+	if len(items) != 3 {
+		t.Fatalf("esperava 3 itens, veio %d", len(items))
+	}
+	if items[0].Photo || items[0].Index != 1 || items[0].Duration != "0:15" {
+		t.Errorf("item 1 deveria ser vídeo: %+v", items[0])
+	}
+	if !items[1].Photo || items[1].imageURL != "https://cdn/p_1080.jpg" {
+		t.Errorf("item 2 deveria ser a maior versão da foto: %+v", items[1])
+	}
+	if !items[2].Photo || items[2].imageURL != "https://cdn/only.jpg" {
+		t.Errorf("item 3 deveria usar a thumbnail: %+v", items[2])
+	}
+
+	selected, err := selectCarouselItems(items, []int{3, 1})
+	if err != nil || len(selected) != 2 || selected[0].Index != 3 || selected[1].Index != 1 {
+		t.Errorf("selectCarouselItems(3,1) = %+v, %v", selected, err)
+	}
+	selected, err = selectCarouselItems(items, []int{2, 2, 1, 2})
+	if err != nil || len(selected) != 2 || selected[0].Index != 2 || selected[1].Index != 1 {
+		t.Errorf("posições repetidas deveriam ser ignoradas: %+v, %v", selected, err)
+	}
+	if _, err := selectCarouselItems(items, []int{4}); err == nil {
+		t.Error("item fora do carrossel deveria falhar")
+	}
+}
+
+func TestInstagramShortcode(t *testing.T) {
+	cases := map[string]string{
+		"https://www.instagram.com/p/C1a2B3c4D5e/":             "C1a2B3c4D5e",
+		"https://www.instagram.com/usuario/p/C1a2B3c4D5e/":     "C1a2B3c4D5e",
+		"https://www.instagram.com/p/C1a2B3c4D5e/?img_index=2": "C1a2B3c4D5e",
+		"https://www.instagram.com/reel/C1a2B3c4D5e/":          "",
+		"https://www.instagram.com/usuario/":                   "",
+	}
+	for raw, want := range cases {
+		if got := instagramShortcode(raw); got != want {
+			t.Errorf("instagramShortcode(%q) = %q; esperava %q", raw, got, want)
+		}
+	}
+	if got := carouselItemID("C1a2B3c4D5e", 2); got != "C1a2B3c4D5e_2" {
+		t.Errorf("carouselItemID = %q", got)
+	}
+}
+
+func TestPhotoExt(t *testing.T) {
+	got := []string{
+		photoExt("https://cdn/a.webp?stp=1", ""),
+		photoExt("https://cdn/a", "image/png"),
+		photoExt("https://cdn/a", ""),
+	}
+	want := []string{".webp", ".png", ".jpg"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("photoExt = %v; esperava %v", got, want)
+	}
+}
@@ -0,0 +1,193 @@
+package downloader
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/diogocardoso/DownloaderTube/internal/i18n"
+)
+
+// Chapter é um capítulo do vídeo, com início e fim em segundos.
+type Chapter struct {
+	Title string  `json:"title"`
+	Start float64 `json:"start"`
+	End   float64 `json:"end"`
+}
+
+// ChapterMode define se o vídeo é separado em um arquivo por capítulo.
+type ChapterMode string
+
+const (
+	// ChaptersSplit gera só os arquivos dos capítulos, sem o vídeo inteiro.
+	ChaptersSplit ChapterMode = "split"
+	// ChaptersBoth gera os capítulos e mantém o vídeo inteiro.
+	ChaptersBoth ChapterMode = "both"
+)
+
+// ParseChapterMode reconhece o nome de um modo de capítulos, sem diferenciar maiúsculas.
+func ParseChapterMode(name string) (ChapterMode, bool) {
+	switch m := ChapterMode(strings.ToLower(strings.TrimSpace(name))); m {
+	case ChaptersSplit, ChaptersBoth:
+		return m, true
+	}
+	return "", false
+}
+
+type ytdlpChapter struct {
+	Title     string  `json:"title"`
+	StartTime float64 `json:"start_time"`
+	EndTime   float64 `json:"end_time"`
+}
+
+var (
+	chaptersPrintRegex = regexp.MustCompile(`^__DT_CHAPTERS__:(.*)$`)
+	unsafeNameRegex    = regexp.MustCompile(`[<>:"/\\|?*\x00-\x1f]+`)
+)
+
+// convertChapters descarta capítulos sem duração e numera os que vieram sem título.
+func convertChapters(raw []ytdlpChapter) []Chapter {
+	var chapters []Chapter
+	for _, c := range raw {
+		if c.EndTime <= c.StartTime {
+			continue
+		}
+		title := strings.TrimSpace(c.Title)
+		if title == "" {
+			title = i18n.Sprintf("Capítulo %d", len(chapters)+1)
+		}
+		chapters = append(chapters, Chapter{Title: title, Start: c.StartTime, End: c.EndTime})
+	}
+	return chapters
+}
+
+// ytdlpChapterArgs faz o yt-dlp imprimir os capítulos ao final do download.
+// Capítulos não se aplicam a trechos nem ao modo somente áudio.
+func ytdlpChapterArgs(req DownloadRequest) []string {
+	if req.Chapters == "" || req.Audio != nil || req.Clip != nil {
+		return nil
+	}
+	return []string{"--print", "after_move:__DT_CHAPTERS__:%(chapters)j"}
+}
+
+// parseChaptersLine lê os capítulos impressos por ytdlpChapterArgs.
+func parseChaptersLine(line string) ([]Chapter, bool) {
+	m := chaptersPrintRegex.FindStringSubmatch(strings.TrimSpace(line))
+	if len(m) < 2 {
+		return nil, false
+	}
+	var raw []ytdlpChapter
+	if err := json.Unmarshal([]byte(m[1]), &raw); err != nil {
+		return nil, true
+	}
+	return convertChapters(raw), true
+}
+
+// splitChapters grava cada capítulo de videoPath em um arquivo próprio,
+// nomeado com o índice e o título (<video>_01_<título>.mp4). O corte é
+// recodificado em H.264/AAC para começar exatamente no início do capítulo; com
+// a conversão desligada, os streams são copiados e o corte cai no keyframe.
+// Retorna os arquivos gerados e um aviso para os capítulos que falharam.
+func splitChapters(ctx context.Context, videoPath string, chapters []Chapter, progress ProgressFunc) ([]string, string) {
+	transcodeMu.Lock()
+	off, preset, crf := transcodeOff, transcodePreset, strconv.Itoa(transcodeCRF)
+	transcodeMu.Unlock()
+
+	ext := ".mp4"
+	if off {
+		ext = filepath.Ext(videoPath)
+	}
+	base := strings.TrimSuffix(videoPath, filepath.Ext(videoPath))
+	width := len(strconv.Itoa(len(chapters)))
+	if width < 2 {
+		width = 2
+	}
+
+	var files []string
+	var warnings []string
+	for i, ch := range chapters {
+		target := fmt.Sprintf("%s_%0*d_%s%s", base, width, i+1, chapterFileTitle(ch.Title), ext)
+
+		args := []string{
+			"-y",
+			"-loglevel", "error",
+			"-nostats",
+			"-progress", "pipe:1",
+			"-ss", strconv.FormatFloat(ch.Start, 'f', 3, 64),
+			"-i", videoPath,
+			"-t", strconv.FormatFloat(ch.End-ch.Start, 'f', 3, 64),
+			"-map", "0:v:0?",
+			"-map", "0:a?",
+		}
+		if off {
+			args = append(args, "-c", "copy")
+		} else {
+			args = append(args,
+				"-c:v", "libx264",
+				"-pix_fmt", "yuv420p",
+				"-profile:v", "high",
+				"-level", "4.1",
+				"-preset", preset,
+				"-crf", crf,
+				"-c:a", "aac",
+				"-b:a", "128k",
+			)
+		}
+		if strings.EqualFold(ext, ".mp4") {
+			args = append(args, "-movflags", "+faststart")
+		}
+		args = append(args, target)
+
+		index, count := i+1, len(chapters)
+		chapterProgress := ProgressFunc(func(ev ProgressEvent) {
+			ev.Phase = PhaseSplitting
+			ev.StreamIndex, ev.StreamCount = index, count
+			progress.emit(ev)
+		})
+		chapterProgress.emit(ProgressEvent{Percent: -1})
+
+		release, err := acquireTranscodeSlot(ctx)
+		if err != nil {
+			removePartialFiles(files)
+			return nil, ""
+		}
+		cmd := exec.CommandContext(ctx, "ffmpeg", args...)
+		prepareCancel(cmd)
+		err = runFFmpegWithProgress(cmd, ch.End-ch.Start, chapterProgress)
+		release()
+
+		if ctx.Err() != nil {
+			os.Remove(target)
+			removePartialFiles(files)
+			return nil, ""
+		}
+		if err != nil {
+			os.Remove(target)
+			debugLogf("[chapters] split %d failed: %v", index, err)
+			warnings = append(warnings, i18n.Sprintf("falha ao separar o capítulo %d (%v)", index, err))
+			continue
+		}
+		files = append(files, target)
+	}
+	return files, joinWarnings(warnings...)
+}
+
+// chapterFileTitle deixa o título do capítulo seguro para nome de arquivo.
+func chapterFileTitle(title string) string {
+	title = unsafeNameRegex.ReplaceAllString(title, " ")
+	title = strings.Join(strings.Fields(title), " ")
+	if r := []rune(title); len(r) > 60 {
+		title = string(r[:60])
+	}
+	title = strings.Trim(title, " .")
+	if title == "" {
+		return "capitulo"
+	}
+	return title
+}
@@ -0,0 +1,41 @@
+package downloader
+
+import "testing"
+
+func TestParseChaptersLine(t *testing.T) {
+	line := `__DT_CHAPTERS__:[{"start_time": 0.0, "end_time": 65.5, "title": "Intro"},` +
+		`{"start_time": 65.5, "end_time": 65.5, "title": "vazio"},` +
+		`{"start_time": 65.5, "end_time": 200.0, "title": ""}]`
+	chapters, ok := parseChaptersLine(line)
+	if !ok || len(chapters) != 2 {
+		t.Fatalf("esperava 2 capítulos, veio %+v (ok=%v)", chapters, ok)
+	}
+	if chapters[0] != (Chapter{Title: "Intro", Start: 0, End: 65.5}) {
+		t.Errorf("primeiro capítulo inesperado: %+v", chapters[0])
+	}
+	if chapters[1].Title != "Capítulo 2" {
+		t.Errorf("capítulo sem título deveria ser numerado: %+v", chapters[1])
+	}
+
+	if chapters, ok := parseChaptersLine("__DT_CHAPTERS__:NA"); !ok || chapters != nil {
+		t.Errorf("vídeo sem capítulos: %+v (ok=%v)", chapters, ok)
+	}
+	if _, ok := parseChaptersLine("__DT_ID__:abc"); ok {
+		t.Errorf("linha de outro marcador não deveria ser reconhecida")
+	}
+}
+
+func TestChapterFileTitle(t *testing.T) {
+	cases := map[string]string{
+		"Parte 1: Início":       "Parte 1 Início",
+		`  a/b\c?  "d"*  `:      "a b c d",
+		"...":                   "capitulo",
+		"Fim do vídeo.":         "Fim do vídeo",
+		"tab\tnova\nlinha<>|ok": "tab nova linha ok",
+	}
+	for in, want := range cases {
+		if got := chapterFileTitle(in); got != want {
+			t.Errorf("chapterFileTitle(%q) = %q; esperava %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package downloader
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/diogocardoso/DownloaderTube/internal/i18n"
+)
+
+// canceledError padroniza o erro de download cancelado; o chamador pode testar
+// com errors.Is(err, context.Canceled).
+func canceledError(ctx context.Context) error {
+	return i18n.Errorf("download cancelado: %w", context.Cause(ctx))
+}
+
+// removePartialFiles apaga os artefatos de um download interrompido: arquivos
+// de destino incompletos, .part/.ytdl, fragmentos e temporários de merge.
+func removePartialFiles(paths []string) {
+	for _, p := range paths {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+
+		ext := filepath.Ext(p)
+		base := strings.TrimSuffix(p, ext)
+		candidates := []string{
+			p,
+			p + ".part",
+			p + ".ytdl",
+			base + ".temp" + ext,
+		}
+
+		// Fragmentos de HLS/DASH: "<arquivo>.part-Frag12". Os títulos costumam ter
+		// colchetes, então a busca é por prefixo e não por glob.
+		dir := filepath.Dir(p)
+		prefix := filepath.Base(p) + ".part-Frag"
+		if entries, err := os.ReadDir(dir); err == nil {
+			for _, e := range entries {
+				if strings.HasPrefix(e.Name(), prefix) {
+					candidates = append(candidates, filepath.Join(dir, e.Name()))
+				}
+			}
+		}
+
+		for _, c := range candidates {
+			if err := os.Remove(c); err == nil {
+				debugLogf("[cleanup] removed %s", c)
+			}
+		}
+	}
+}
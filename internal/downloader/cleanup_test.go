@@ -0,0 +1,37 @@
+package downloader
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRemovePartialFiles(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, "Vídeo [abc].f137.mp4")
+
+	partial := []string{
+		target + ".part",
+		target + ".ytdl",
+		target + ".part-Frag1",
+		target + ".part-Frag2",
+		filepath.Join(dir, "Vídeo [abc].f137.temp.mp4"),
+	}
+	kept := filepath.Join(dir, "outro.mp4")
+	for _, p := range append(partial, kept) {
+		if err := os.WriteFile(p, []byte("x"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	removePartialFiles([]string{target, ""})
+
+	for _, p := range partial {
+		if _, err := os.Stat(p); !os.IsNotExist(err) {
+			t.Errorf("%s deveria ter sido removido", filepath.Base(p))
+		}
+	}
+	if _, err := os.Stat(kept); err != nil {
+		t.Errorf("arquivo não relacionado foi removido: %v", err)
+	}
+}
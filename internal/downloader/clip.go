@@ -0,0 +1,117 @@
+package downloader
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/diogocardoso/DownloaderTube/internal/i18n"
+)
+
+// ClipRange limita o download a um trecho do vídeo, em segundos.
+type ClipRange struct {
+	Start float64 `json:"start"`
+	// End é o fim do trecho; 0 = até o fim do vídeo.
+	End float64 `json:"end,omitempty"`
+}
+
+// ParseClipRange monta o trecho a partir dos horários de início e fim (ver
+// ParseTimestamp). Início vazio é o começo do vídeo; fim vazio, o final.
+func ParseClipRange(start, end string) (*ClipRange, error) {
+	var c ClipRange
+	var err error
+	if strings.TrimSpace(start) != "" {
+		if c.Start, err = ParseTimestamp(start); err != nil {
+			return nil, err
+		}
+	}
+	if strings.TrimSpace(end) != "" {
+		if c.End, err = ParseTimestamp(end); err != nil {
+			return nil, err
+		}
+		if c.End <= c.Start {
+			return nil, i18n.Errorf("o fim do trecho (%s) deve ser depois do início (%s)", FormatTimestamp(c.End), FormatTimestamp(c.Start))
+		}
+	}
+	return &c, nil
+}
+
+// ParseTimestamp aceita segundos ("90", "12.5"), "mm:ss" ou "hh:mm:ss", com
+// fração opcional nos segundos ("1:02:03.250").
+func ParseTimestamp(s string) (float64, error) {
+	s = strings.TrimSpace(s)
+	parts := strings.Split(s, ":")
+	if s == "" || len(parts) > 3 {
+		return 0, i18n.Errorf("horário inválido: %q", s)
+	}
+
+	var total float64
+	for i, p := range parts {
+		last := i == len(parts)-1
+		var v float64
+		var err error
+		if last {
+			v, err = strconv.ParseFloat(p, 64)
+		} else {
+			var n int
+			n, err = strconv.Atoi(p)
+			v = float64(n)
+		}
+		// Minutos e segundos depois de ":" não passam de 59.
+		if err != nil || v < 0 || math.IsInf(v, 0) || math.IsNaN(v) || (i > 0 && v >= 60) {
+			return 0, i18n.Errorf("horário inválido: %q", s)
+		}
+		total = total*60 + v
+	}
+	return total, nil
+}
+
+// FormatTimestamp formata segundos como "mm:ss" ou "h:mm:ss", mantendo a fração
+// quando houver.
+func FormatTimestamp(sec float64) string {
+	whole := int(sec)
+	frac := ""
+	if f := sec - float64(whole); f > 0.0005 {
+		frac = strings.TrimPrefix(strconv.FormatFloat(f, 'f', 3, 64), "0")
+		frac = strings.TrimRight(frac, "0")
+	}
+	h, m, s := whole/3600, whole/60%60, whole%60
+	if h > 0 {
+		return fmt.Sprintf("%d:%02d:%02d%s", h, m, s, frac)
+	}
+	return fmt.Sprintf("%02d:%02d%s", m, s, frac)
+}
+
+// Label descreve o trecho para exibição (ex: "01:30 - 02:10").
+func (c ClipRange) Label() string {
+	if c.End <= 0 {
+		return i18n.Sprintf("%s até o fim", FormatTimestamp(c.Start))
+	}
+	return FormatTimestamp(c.Start) + " - " + FormatTimestamp(c.End)
+}
+
+// fileSuffix identifica o trecho no nome do arquivo, para que recortes do
+// mesmo vídeo não se confundam com o vídeo inteiro (ex: "clip_90-130").
+func (c ClipRange) fileSuffix() string {
+	end := "end"
+	if c.End > 0 {
+		end = strconv.Itoa(int(math.Ceil(c.End)))
+	}
+	return fmt.Sprintf("clip_%d-%s", int(c.Start), end)
+}
+
+// ytdlpClipArgs pede só o trecho ao yt-dlp. --force-keyframes-at-cuts
+// recodifica nas bordas para o corte cair no horário pedido e não no keyframe
+// mais próximo.
+func ytdlpClipArgs(req DownloadRequest) []string {
+	if req.Clip == nil {
+		return nil
+	}
+	end := "inf"
+	if req.Clip.End > 0 {
+		end = strconv.FormatFloat(req.Clip.End, 'f', -1, 64)
+	}
+	section := "*" + strconv.FormatFloat(req.Clip.Start, 'f', -1, 64) + "-" + end
+	return []string{"--download-sections", section, "--force-keyframes-at-cuts"}
+}
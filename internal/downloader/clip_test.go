@@ -0,0 +1,51 @@
+package downloader
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseTimestamp(t *testing.T) {
+	cases := map[string]float64{
+		"90":          90,
+		"12.5":        12.5,
+		"1:30":        90,
+		"01:02:03.25": 3723.25,
+	}
+	for in, want := range cases {
+		if got, err := ParseTimestamp(in); err != nil || got != want {
+			t.Errorf("ParseTimestamp(%q) = %v, %v; esperava %v", in, got, err, want)
+		}
+	}
+	for _, in := range []string{"", "1:60", "a:10", "1:2:3:4", "-5"} {
+		if _, err := ParseTimestamp(in); err == nil {
+			t.Errorf("ParseTimestamp(%q) deveria falhar", in)
+		}
+	}
+}
+
+func TestClipRange(t *testing.T) {
+	if _, err := ParseClipRange("2:10", "1:30"); err == nil {
+		t.Errorf("fim antes do início deveria falhar")
+	}
+
+	c, err := ParseClipRange("1:30", "2:10.5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := c.Label(); got != "01:30 - 02:10.5" {
+		t.Errorf("Label = %q", got)
+	}
+	if got := c.fileSuffix(); got != "clip_90-131" {
+		t.Errorf("fileSuffix = %q", got)
+	}
+
+	want := []string{"--download-sections", "*90-130.5", "--force-keyframes-at-cuts"}
+	if got := ytdlpClipArgs(DownloadRequest{Clip: c}); !reflect.DeepEqual(got, want) {
+		t.Errorf("ytdlpClipArgs = %v", got)
+	}
+	open := DownloadRequest{Clip: &ClipRange{Start: 3723}}
+	if got := ytdlpClipArgs(open); got[1] != "*3723-inf" {
+		t.Errorf("trecho até o fim = %v", got)
+	}
+}
@@ -0,0 +1,117 @@
+package downloader
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/diogocardoso/DownloaderTube/internal/i18n"
+)
+
+// CollectionRequest descreve o download dos itens de um canal, perfil ou
+// playlist em uma única execução do yt-dlp, pulando o que já foi baixado.
+type CollectionRequest struct {
+	URL string
+	// Height é a altura máxima dos vídeos; 0 = melhor disponível.
+	Height int
+	// Audio, quando definido, baixa só o áudio de cada item.
+	Audio *AudioOptions
+	Dest  string
+	// Archive é o arquivo --download-archive do yt-dlp. Itens registrados nele
+	// são pulados; em canais, que listam do mais novo para o mais antigo, a
+	// busca para no primeiro deles.
+	Archive string
+	// DateAfter, quando definido, ignora itens publicados antes dessa data; em
+	// canais, a busca para no primeiro deles.
+	DateAfter time.Time
+	// Limit é o máximo de itens verificados por execução; 0 = sem limite.
+	Limit int
+	// RateLimit é a velocidade máxima em bytes por segundo; 0 = sem limite.
+	RateLimit int64
+}
+
+// CollectionDownloader é implementado pelos Downloaders que sabem baixar os
+// itens novos de um canal ou perfil de uma vez.
+type CollectionDownloader interface {
+	DownloadCollection(ctx context.Context, req CollectionRequest, progress ProgressFunc) ([]DownloadResult, error)
+}
+
+// ytdlpBreakExitCode é o código de saída do yt-dlp quando a busca é
+// interrompida de propósito (--break-on-existing, --break-match-filters).
+const ytdlpBreakExitCode = 101
+
+// itemRequest é o DownloadRequest aplicado a cada item da coleção.
+func (r CollectionRequest) itemRequest() DownloadRequest {
+	height := r.Height
+	if height <= 0 {
+		// Os formatos de vídeo filtram por altura; sem limite, vale qualquer uma.
+		height = 99999
+	}
+	return DownloadRequest{URL: r.URL, Height: height, Dest: r.Dest, Audio: r.Audio, RateLimit: r.RateLimit}
+}
+
+// ytdlpCollectionArgs traduz arquivo, data e limite da coleção para o yt-dlp.
+// Vem depois dos argumentos de download para que --yes-playlist prevaleça.
+// breakEarly para a busca no primeiro item já baixado ou anterior à data, o
+// que só vale em listas do mais novo para o mais antigo; sem ele, esses itens
+// são pulados e a busca segue até Limit (perfis do Instagram põem os posts
+// fixados no topo). filters são condições que todo item precisa atender; vão
+// em um só --match-filters, pois vários são combinados com "ou".
+func ytdlpCollectionArgs(req CollectionRequest, breakEarly bool, filters ...string) []string {
+	args := []string{"--yes-playlist", "--ignore-errors"}
+	if req.Archive != "" {
+		args = append(args, "--download-archive", req.Archive)
+		if breakEarly {
+			args = append(args, "--break-on-existing")
+		}
+	}
+	if !req.DateAfter.IsZero() {
+		dateFilter := "upload_date>=" + req.DateAfter.Format("20060102")
+		if breakEarly {
+			args = append(args, "--break-match-filters", dateFilter)
+		} else {
+			filters = append(filters, dateFilter)
+		}
+	}
+	if len(filters) > 0 {
+		args = append(args, "--match-filters", strings.Join(filters, " & "))
+	}
+	if req.Limit > 0 {
+		args = append(args, "--playlist-end", strconv.Itoa(req.Limit))
+	}
+	return args
+}
+
+// runYtDlpCollection executa o yt-dlp e passa cada item concluído pelo mesmo
+// acabamento de um download avulso (WhatsApp e nome <plataforma>_<id>).
+// Retorna os itens baixados mesmo quando alguns falham; nesse caso o erro
+// descreve a falha.
+func runYtDlpCollection(ctx context.Context, platform string, args []string, cookies Cookies, req CollectionRequest, progress ProgressFunc) ([]DownloadResult, error) {
+	startedAt := time.Now()
+	out, err := runYtDlpDownloadWithCookies(ctx, platform, args, cookies, progress)
+	if ctx.Err() != nil {
+		return nil, err
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == ytdlpBreakExitCode {
+		debugLogf("[%s] collection stopped at archived or older item", platform)
+		err = nil
+	}
+
+	item := req.itemRequest()
+	results := make([]DownloadResult, 0, len(out.Items))
+	for _, it := range out.Items {
+		res, ferr := finalizeDownload(ctx, platform, ytdlpOutput{FilePath: it.FilePath, MediaID: it.MediaID}, item, startedAt, progress)
+		if ferr != nil {
+			return results, ferr
+		}
+		results = append(results, res)
+	}
+	if err != nil {
+		return results, i18n.Errorf("%d item(ns) baixado(s), mas houve falhas: %w", len(results), err)
+	}
+	return results, nil
+}
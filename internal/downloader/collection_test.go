@@ -0,0 +1,52 @@
+package downloader
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestYtdlpCollectionArgs(t *testing.T) {
+	req := CollectionRequest{
+		Archive:   "/tmp/archive.txt",
+		DateAfter: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+		Limit:     20,
+	}
+	want := []string{
+		"--yes-playlist", "--ignore-errors",
+		"--download-archive", "/tmp/archive.txt", "--break-on-existing",
+		"--break-match-filters", "upload_date>=20260301",
+		"--playlist-end", "20",
+	}
+	if got := ytdlpCollectionArgs(req, true); !reflect.DeepEqual(got, want) {
+		t.Errorf("ytdlpCollectionArgs = %v", got)
+	}
+
+	// Sem parar na primeira correspondência, a data vira filtro junto dos demais.
+	want = []string{
+		"--yes-playlist", "--ignore-errors",
+		"--download-archive", "/tmp/archive.txt",
+		"--match-filters", "vcodec!=none & upload_date>=20260301",
+		"--playlist-end", "20",
+	}
+	if got := ytdlpCollectionArgs(req, false, "vcodec!=none"); !reflect.DeepEqual(got, want) {
+		t.Errorf("ytdlpCollectionArgs sem parada = %v", got)
+	}
+	if got := ytdlpCollectionArgs(CollectionRequest{}, true); len(got) != 2 {
+		t.Errorf("sem arquivo, data nem limite, esperava só os argumentos de playlist: %v", got)
+	}
+	if h := (CollectionRequest{}).itemRequest().Height; h <= 2160 {
+		t.Errorf("altura 0 deveria virar sem limite, veio %d", h)
+	}
+}
+
+func TestPartialArtifactsKeepsFinishedItems(t *testing.T) {
+	out := ytdlpOutput{
+		Items:     []ytdlpItem{{FilePath: "/d/a.mp4", MediaID: "a"}},
+		artifacts: []string{"/d/a.f137.mp4", "/d/a.mp4", "/d/b.f137.mp4.part"},
+	}
+	want := []string{"/d/a.f137.mp4", "/d/b.f137.mp4.part"}
+	if got := out.partialArtifacts(); !reflect.DeepEqual(got, want) {
+		t.Errorf("partialArtifacts = %v; esperava %v", got, want)
+	}
+}
@@ -0,0 +1,98 @@
+package downloader
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"strings"
+)
+
+// Cookies indica de onde o yt-dlp lê a sessão logada de uma plataforma: um
+// arquivo cookies.txt (formato Netscape) ou um navegador no formato
+// NAVEGADOR[+CHAVEIRO][:PERFIL]. Com os dois definidos, vale o arquivo.
+type Cookies struct {
+	Browser string
+	File    string
+}
+
+// Enabled indica se há cookies configurados.
+func (c Cookies) Enabled() bool {
+	return len(c.args()) > 0
+}
+
+// String descreve a origem dos cookies para logs e telas.
+func (c Cookies) String() string {
+	if f := strings.TrimSpace(c.File); f != "" {
+		return f
+	}
+	return strings.TrimSpace(c.Browser)
+}
+
+func (c Cookies) args() []string {
+	if f := strings.TrimSpace(c.File); f != "" {
+		return []string{"--cookies", f}
+	}
+	return cookiesFromBrowserArgs(c.Browser)
+}
+
+// cookiesFromBrowserArgs faz o yt-dlp ler os cookies do navegador; sem
+// navegador não adiciona argumentos.
+func cookiesFromBrowserArgs(browser string) []string {
+	browser = strings.TrimSpace(browser)
+	if browser == "" {
+		return nil
+	}
+	return []string{"--cookies-from-browser", browser}
+}
+
+// shouldRetryWithoutCookies indica se o yt-dlp falhou por não conseguir ler
+// os cookies (navegador fechado sem perfil, banco bloqueado, arquivo inválido).
+// A mensagem do yt-dlp fica no stderr guardado em *exec.ExitError.
+func shouldRetryWithoutCookies(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := err.Error()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		msg += "\n" + string(exitErr.Stderr)
+	}
+	return isCookieError(msg)
+}
+
+func isCookieError(msg string) bool {
+	msg = strings.ToLower(msg)
+	return strings.Contains(msg, "cookie database") ||
+		strings.Contains(msg, "cookies-from-browser") ||
+		strings.Contains(msg, "netscape format") ||
+		strings.Contains(msg, "cookiefile") ||
+		strings.Contains(msg, "cookies file")
+}
+
+// runYtDlpInfo roda o yt-dlp para ler metadados com os cookies e, se eles não
+// puderem ser lidos, repete sem cookies.
+func runYtDlpInfo(ctx context.Context, platform string, args []string, cookies Cookies) ([]byte, error) {
+	cookieArgs := cookies.args()
+	output, err := exec.CommandContext(ctx, "yt-dlp", append(cookieArgs, args...)...).Output()
+	if err != nil && len(cookieArgs) > 0 && ctx.Err() == nil && shouldRetryWithoutCookies(err) {
+		debugLogf("[%s] cookies failed, retry without cookies: %v", platform, err)
+		output, err = exec.CommandContext(ctx, "yt-dlp", args...).Output()
+	}
+	return output, err
+}
+
+// runYtDlpDownloadWithCookies é o runYtDlpDownload com os cookies da
+// plataforma, repetindo sem eles quando o yt-dlp não consegue lê-los.
+func runYtDlpDownloadWithCookies(ctx context.Context, platform string, args []string, cookies Cookies, progress ProgressFunc) (ytdlpOutput, error) {
+	cookieArgs := cookies.args()
+	if len(cookieArgs) == 0 {
+		return runYtDlpDownload(ctx, platform, args, progress)
+	}
+	debugLogf("[%s] cookies enabled: %s", platform, cookies)
+	out, err := runYtDlpDownload(ctx, platform, append(cookieArgs, args...), progress)
+	if err != nil && ctx.Err() == nil && out.cookieFailed {
+		debugLogf("[%s] cookies failed, retry without cookies: %v", platform, err)
+		return runYtDlpDownload(ctx, platform, args, progress)
+	}
+	return out, err
+}
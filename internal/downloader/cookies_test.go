@@ -0,0 +1,37 @@
+package downloader
+
+import (
+	"errors"
+	"os/exec"
+	"reflect"
+	"testing"
+)
+
+func TestCookiesArgs(t *testing.T) {
+	if got := (Cookies{}).args(); len(got) != 0 {
+		t.Fatalf("esperava sem args quando não há cookies, veio: %v", got)
+	}
+
+	got := Cookies{Browser: "chrome"}.args()
+	if !reflect.DeepEqual(got, []string{"--cookies-from-browser", "chrome"}) {
+		t.Fatalf("args inesperados: %v", got)
+	}
+
+	got = Cookies{Browser: "chrome", File: "/tmp/cookies.txt"}.args()
+	if !reflect.DeepEqual(got, []string{"--cookies", "/tmp/cookies.txt"}) {
+		t.Fatalf("o arquivo deveria ter precedência sobre o navegador: %v", got)
+	}
+}
+
+func TestShouldRetryWithoutCookies(t *testing.T) {
+	stderr := &exec.ExitError{Stderr: []byte("ERROR: Could not copy Chrome cookie database. See https://github.com/yt-dlp/yt-dlp/issues/7271")}
+	if !shouldRetryWithoutCookies(stderr) {
+		t.Error("falha ao ler o navegador deveria repetir sem cookies")
+	}
+	if !isCookieError("ERROR: '/tmp/c.txt' does not look like a Netscape format cookies file") {
+		t.Error("arquivo inválido deveria repetir sem cookies")
+	}
+	if shouldRetryWithoutCookies(errors.New("exit status 1")) {
+		t.Error("erro sem relação com cookies não deveria repetir")
+	}
+}
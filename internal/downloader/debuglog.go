@@ -14,6 +14,8 @@ func debugLogPath() string {
 	return filepath.Join(os.TempDir(), "downloadertube-debug.log")
 }
 
+// debugLogf grava uma linha no log de depuração. Usuário e senha embutidos em
+// URLs (como as do proxy) são escondidos.
 func debugLogf(format string, args ...any) {
 	debugLogMu.Lock()
 	defer debugLogMu.Unlock()
@@ -25,5 +27,5 @@ func debugLogf(format string, args ...any) {
 	defer f.Close()
 
 	ts := time.Now().Format("2006-01-02 15:04:05.000")
-	_, _ = fmt.Fprintf(f, "%s %s\n", ts, fmt.Sprintf(format, args...))
+	_, _ = fmt.Fprintf(f, "%s %s\n", ts, RedactProxy(fmt.Sprintf(format, args...)))
 }
@@ -1,33 +1,101 @@
 package downloader
 
+import "context"
+
 // VideoInfo contém os metadados de um vídeo.
 type VideoInfo struct {
-	Title     string
-	Duration  string
-	Formats   []Format
-	Languages []AudioLang
+	Title     string      `json:"title"`
+	Duration  string      `json:"duration,omitempty"`
+	Formats   []Format    `json:"formats"`
+	Languages []AudioLang `json:"languages"`
+	// AudioTracks são as faixas só de áudio, usadas no modo somente áudio.
+	AudioTracks []AudioTrack `json:"audio_tracks,omitempty"`
+	// Subtitles são as legendas disponíveis, manuais antes das automáticas.
+	Subtitles []SubtitleTrack `json:"subtitles,omitempty"`
+	// Chapters são os capítulos do vídeo, quando a plataforma os informa.
+	Chapters []Chapter `json:"chapters,omitempty"`
+	// Items são os itens de um carrossel do Instagram, na ordem do post.
+	Items []CarouselItem `json:"items,omitempty"`
 }
 
 // Format representa uma opção de qualidade disponível.
 type Format struct {
-	Height int
-	Label  string
+	Height int    `json:"height"`
+	Label  string `json:"label"`
 }
 
 // AudioLang representa um idioma de áudio disponível.
 type AudioLang struct {
-	Code string
-	Name string
+	Code string `json:"code"`
+	Name string `json:"name"`
+}
+
+// AudioTrack representa uma faixa só de áudio disponível, por taxa de bits.
+type AudioTrack struct {
+	Bitrate int    `json:"bitrate"` // kbps
+	Codec   string `json:"codec,omitempty"`
+	Label   string `json:"label"`
+}
+
+// DownloadRequest descreve o que baixar.
+type DownloadRequest struct {
+	URL string
+	// Height é a altura máxima do vídeo; ignorada no modo somente áudio.
+	Height int
+	// LangCode é o idioma do áudio; com ExtraLangs, é a faixa padrão.
+	LangCode string
+	// ExtraLangs são outros idiomas de áudio mantidos no mesmo arquivo, cada
+	// um em sua faixa, depois da de LangCode. Só no YouTube e fora do modo
+	// somente áudio.
+	ExtraLangs []string
+	// WhatsAppCopy, com ExtraLangs, salva também uma cópia <nome>_whatsapp.mp4
+	// só com a faixa padrão, já que o WhatsApp toca uma faixa só.
+	WhatsAppCopy bool
+	// Dest é a pasta de destino.
+	Dest string
+	// Audio, quando definido, baixa só o áudio e o extrai no formato pedido.
+	Audio *AudioOptions
+	// Subtitles, quando definido, baixa as legendas junto com o vídeo.
+	Subtitles *SubtitleOptions
+	// Clip, quando definido, baixa só o trecho indicado.
+	Clip *ClipRange
+	// Chapters, quando definido, separa o vídeo em um arquivo por capítulo.
+	// Ignorado com Clip e no modo somente áudio.
+	Chapters ChapterMode
+	// FilePrefix é acrescentado ao início do nome final do arquivo (ex: "03_"
+	// para a posição na playlist).
+	FilePrefix string
+	// Items restringe um carrossel do Instagram às posições indicadas
+	// (1 = primeiro item); vazio baixa todos.
+	Items []int
+	// RateLimit é a velocidade máxima do download em bytes por segundo; 0 =
+	// sem limite.
+	RateLimit int64
 }
 
 // DownloadResult contém o resultado de um download bem-sucedido.
 type DownloadResult struct {
-	FilePath             string
-	CompatibilityWarning string
+	FilePath             string `json:"file_path"`
+	MediaID              string `json:"media_id,omitempty"`
+	CompatibilityWarning string `json:"compatibility_warning,omitempty"`
+	// Subtitles são os arquivos .srt salvos ao lado do vídeo.
+	Subtitles       []string `json:"subtitles,omitempty"`
+	SubtitleWarning string   `json:"subtitle_warning,omitempty"`
+	// Files são os arquivos gerados quando o download produz vários: os
+	// capítulos, na ordem do vídeo, ou os itens de um carrossel, na ordem do post.
+	Files          []string `json:"files,omitempty"`
+	ChapterWarning string   `json:"chapter_warning,omitempty"`
+	// ItemWarning descreve os itens do carrossel que não puderam ser baixados.
+	ItemWarning string `json:"item_warning,omitempty"`
+	// WhatsAppFile é a cópia só com a faixa de áudio padrão (ver
+	// DownloadRequest.WhatsAppCopy).
+	WhatsAppFile string `json:"whatsapp_file,omitempty"`
 }
 
 // Downloader define a interface para qualquer plataforma de download.
+// Cancelar ctx interrompe o yt-dlp/ffmpeg e remove os arquivos parciais; o erro
+// retornado nesse caso satisfaz errors.Is(err, context.Canceled).
 type Downloader interface {
-	GetVideoInfo(url string) (*VideoInfo, error)
-	Download(url string, height int, langCode string, dest string, progress func(current, total int64)) (DownloadResult, error)
+	GetVideoInfo(ctx context.Context, url string) (*VideoInfo, error)
+	Download(ctx context.Context, req DownloadRequest, progress ProgressFunc) (DownloadResult, error)
 }
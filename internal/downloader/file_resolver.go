@@ -44,7 +44,7 @@ func resolveDownloadedFile(parsedPath, destDir, mediaID string, startedAt time.T
 
 		ext := strings.ToLower(filepath.Ext(e.Name()))
 		switch ext {
-		case ".mp4", ".mkv", ".webm", ".mov", ".m4v":
+		case ".mp4", ".mkv", ".webm", ".mov", ".m4v", ".mp3", ".m4a", ".opus":
 			candidates = append(candidates, fileCandidate{
 				path:    filepath.Join(destDir, e.Name()),
 				modTime: info.ModTime(),
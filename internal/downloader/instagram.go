@@ -4,16 +4,23 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"os/exec"
 	"path/filepath"
 	"sort"
 	"strconv"
 	"strings"
-	"sync"
 	"time"
+
+	"github.com/diogocardoso/DownloaderTube/internal/i18n"
 )
 
-type InstagramDownloader struct{}
+type InstagramDownloader struct {
+	// Cookies, quando definidos, autenticam o yt-dlp no Instagram. Stories,
+	// destaques e perfis privados só são acessíveis com login.
+	Cookies Cookies
+	// Proxy é repassado ao yt-dlp em --proxy e usado também ao baixar as fotos
+	// de um carrossel.
+	Proxy string
+}
 
 type ytdlpPlaylistInfo struct {
 	ytdlpInfo
@@ -24,19 +31,10 @@ func NewInstagram() *InstagramDownloader {
 	return &InstagramDownloader{}
 }
 
-func (id *InstagramDownloader) GetVideoInfo(rawURL string) (*VideoInfo, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
-
-	cmd := exec.CommandContext(ctx, "yt-dlp", "-J", "--no-warnings", rawURL)
-	output, err := cmd.Output()
+func (id *InstagramDownloader) GetVideoInfo(ctx context.Context, rawURL string) (*VideoInfo, error) {
+	playlistInfo, err := id.fetchInfo(ctx, rawURL)
 	if err != nil {
-		return nil, fmt.Errorf("erro ao obter info do vídeo: %w", err)
-	}
-
-	var playlistInfo ytdlpPlaylistInfo
-	if err := json.Unmarshal(output, &playlistInfo); err != nil {
-		return nil, fmt.Errorf("erro ao parsear info do vídeo: %w", err)
+		return nil, err
 	}
 
 	info := pickInstagramInfo(playlistInfo)
@@ -63,120 +61,104 @@ func (id *InstagramDownloader) GetVideoInfo(rawURL string) (*VideoInfo, error) {
 	})
 
 	return &VideoInfo{
-		Title:    info.Title,
-		Duration: info.DurationString,
-		Formats:  formats,
+		Title:       info.Title,
+		Duration:    info.DurationString,
+		Formats:     formats,
+		AudioTracks: collectAudioTracks(info.Formats),
+		Subtitles:   collectSubtitles(info.Subtitles, info.AutomaticCaptions, info.Language),
+		Chapters:    convertChapters(info.Chapters),
+		Items:       carouselItems(playlistInfo.Entries),
 	}, nil
 }
 
-func (id *InstagramDownloader) Download(videoURL string, height int, langCode string, dest string, progress func(current, total int64)) (DownloadResult, error) {
-	outputTemplate := filepath.Join(dest, "%(title)s.%(ext)s")
-	formatStr := buildInstagramFormatString(height)
-	startedAt := time.Now()
-	debugLogf("[instagram] start url=%s height=%d format=%s", videoURL, height, formatStr)
-
-	cmd := exec.Command("yt-dlp",
-		"-f", formatStr,
-		"--progress",
-		"--progress-template", "download:__DT_PROGRESS__:%(progress.downloaded_bytes)s:%(progress.total_bytes)s:%(progress.total_bytes_estimate)s:%(progress._percent_str)s",
-		"--yes-playlist",
-		"--ignore-errors",
-		"--match-filter", "vcodec!=none",
-		"--merge-output-format", "mp4",
-		"--embed-metadata",
-		"--print", "after_move:__DT_PATH__:%(filepath)s",
-		"--print", "after_move:__DT_ID__:%(id)s",
-		"--newline",
-		"--no-warnings",
-		"-o", outputTemplate,
-		videoURL,
-	)
-
-	stdoutPipe, err := cmd.StdoutPipe()
-	if err != nil {
-		return DownloadResult{}, fmt.Errorf("erro ao criar pipe stdout: %w", err)
-	}
+// fetchInfo lê os metadados do post. Fotos não têm formato de vídeo, então o
+// erro "No video formats" é ignorado para que os itens de um carrossel apareçam.
+func (id *InstagramDownloader) fetchInfo(ctx context.Context, rawURL string) (ytdlpPlaylistInfo, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
 
-	stderrPipe, err := cmd.StderrPipe()
+	args := []string{"-J", "--no-warnings", "--ignore-no-formats-error"}
+	args = append(args, proxyArgs("instagram", id.Proxy)...)
+	output, err := runYtDlpInfo(ctx, "instagram", append(args, rawURL), id.Cookies)
 	if err != nil {
-		return DownloadResult{}, fmt.Errorf("erro ao criar pipe stderr: %w", err)
+		return ytdlpPlaylistInfo{}, i18n.Errorf("erro ao obter info do vídeo: %w", err)
 	}
 
-	if err := cmd.Start(); err != nil {
-		debugLogf("[instagram] cmd start error: %v", err)
-		return DownloadResult{}, fmt.Errorf("erro ao iniciar yt-dlp: %w", err)
+	var playlistInfo ytdlpPlaylistInfo
+	if err := json.Unmarshal(output, &playlistInfo); err != nil {
+		return ytdlpPlaylistInfo{}, i18n.Errorf("erro ao parsear info do vídeo: %w", err)
 	}
+	return playlistInfo, nil
+}
 
-	var filePath string
-	var mediaID string
-	var mu sync.Mutex
-
-	parseLine := func(line string) {
-		if strings.Contains(line, "[download]") || strings.Contains(line, "ERROR") || strings.Contains(line, "WARNING") {
-			debugLogf("[instagram] line: %s", line)
-		}
-		if progress != nil {
-			if currentVal, totalVal, ok := parseProgressLine(line); ok {
-				debugLogf("[instagram] progress parsed current=%d total=%d", currentVal, totalVal)
-				progress(currentVal, totalVal)
-			}
+func (id *InstagramDownloader) Download(ctx context.Context, req DownloadRequest, progress ProgressFunc) (DownloadResult, error) {
+	progress = progress.withRateLimit(req.RateLimit)
+	// Só posts (/p/) podem ser carrosséis; reels seguem direto para o yt-dlp.
+	if instagramShortcode(req.URL) != "" {
+		progress.emit(ProgressEvent{Phase: PhaseExtracting, Percent: -1})
+		info, err := id.fetchInfo(ctx, req.URL)
+		if ctx.Err() != nil {
+			return DownloadResult{}, canceledError(ctx)
 		}
-		if p := extractFilePath(line); p != "" {
-			debugLogf("[instagram] file path detected: %s", p)
-			mu.Lock()
-			filePath = p
-			mu.Unlock()
+		if err != nil {
+			return DownloadResult{}, err
 		}
-		if id := extractMediaID(line); id != "" {
-			debugLogf("[instagram] media id detected: %s", id)
-			mu.Lock()
-			mediaID = id
-			mu.Unlock()
+		if len(info.Entries) > 0 {
+			return id.downloadCarousel(ctx, req, info, progress)
 		}
 	}
 
-	var wg sync.WaitGroup
-	wg.Add(2)
-
-	go func() {
-		defer wg.Done()
-		scanner := newProgressScanner(stdoutPipe)
-		for scanner.Scan() {
-			parseLine(scanner.Text())
-		}
-		if err := scanner.Err(); err != nil {
-			debugLogf("[instagram] stdout scanner error: %v", err)
-		}
-	}()
+	startedAt := time.Now()
+	args := append(id.downloadArgs(req),
+		"--yes-playlist",
+		"--ignore-errors",
+		"--match-filter", "vcodec!=none",
+		req.URL,
+	)
 
-	go func() {
-		defer wg.Done()
-		scanner := newProgressScanner(stderrPipe)
-		for scanner.Scan() {
-			parseLine(scanner.Text())
-		}
-		if err := scanner.Err(); err != nil {
-			debugLogf("[instagram] stderr scanner error: %v", err)
-		}
-	}()
+	out, err := runYtDlpDownloadWithCookies(ctx, "instagram", args, id.Cookies, progress)
+	if err != nil {
+		return DownloadResult{}, err
+	}
 
-	wg.Wait()
+	return finalizeDownload(ctx, "instagram", out, req, startedAt, progress)
+}
 
-	if err := cmd.Wait(); err != nil {
-		debugLogf("[instagram] cmd wait error: %v", err)
-		return DownloadResult{}, fmt.Errorf("erro durante download: %w", err)
+// DownloadCollection baixa os vídeos de um perfil, da aba de reels, dos
+// stories ou de um destaque (ver CollectionRequest). Fotos são ignoradas.
+func (id *InstagramDownloader) DownloadCollection(ctx context.Context, req CollectionRequest, progress ProgressFunc) ([]DownloadResult, error) {
+	progress = progress.withRateLimit(req.RateLimit)
+	args := id.downloadArgs(req.itemRequest())
+	// Posts fixados no topo do perfil quebram a ordem por data: itens antigos
+	// ou já baixados são pulados em vez de encerrar a busca.
+	args = append(args, ytdlpCollectionArgs(req, false, "vcodec!=none")...)
+	args = append(args, req.URL)
+	results, err := runYtDlpCollection(ctx, "instagram", args, id.Cookies, req, progress)
+	if err != nil && len(results) == 0 && !id.Cookies.Enabled() && strings.Contains(req.URL, "/stories/") {
+		err = i18n.Errorf("%w (stories e destaques exigem login: configure os cookies do Instagram)", err)
 	}
+	return results, err
+}
 
-	resolvedPath := resolveDownloadedFile(filePath, dest, mediaID, startedAt)
-	debugLogf("[instagram] resolved path parsed=%s resolved=%s", filePath, resolvedPath)
-	finalPath, warning := ensureWhatsAppCompatible(resolvedPath)
-	namedPath, nameWarning := ensurePlatformFileName(finalPath, "instagram", mediaID)
-	debugLogf("[instagram] done filePath=%s finalPath=%s namedPath=%s mediaID=%s warning=%s nameWarning=%s", resolvedPath, finalPath, namedPath, mediaID, warning, nameWarning)
-
-	return DownloadResult{
-		FilePath:             namedPath,
-		CompatibilityWarning: joinWarnings(warning, nameWarning),
-	}, nil
+// downloadArgs monta os argumentos do yt-dlp para req, sem a URL.
+func (id *InstagramDownloader) downloadArgs(req DownloadRequest) []string {
+	outputTemplate := filepath.Join(req.Dest, "%(title)s.%(ext)s")
+
+	var args []string
+	if req.Audio != nil {
+		formatStr := buildAudioFormatString(req.Audio.Bitrate, "")
+		debugLogf("[instagram] start audio url=%s audio=%s bitrate=%d format=%s", req.URL, req.Audio.Format, req.Audio.Bitrate, formatStr)
+		args = ytdlpAudioArgs(formatStr, outputTemplate, req.Audio.Format)
+	} else {
+		formatStr := buildInstagramFormatString(req.Height)
+		debugLogf("[instagram] start url=%s height=%d format=%s", req.URL, req.Height, formatStr)
+		args = ytdlpDownloadArgs(formatStr, outputTemplate)
+	}
+	args = append(args, ytdlpSubtitleArgs(req)...)
+	args = append(args, ytdlpClipArgs(req)...)
+	args = append(args, ytdlpChapterArgs(req)...)
+	args = append(args, ytdlpRateArgs(req)...)
+	return append(args, proxyArgs("instagram", id.Proxy)...)
 }
 
 func buildInstagramFormatString(height int) string {
@@ -190,10 +172,8 @@ func pickInstagramInfo(playlistInfo ytdlpPlaylistInfo) ytdlpInfo {
 	}
 
 	for _, entry := range playlistInfo.Entries {
-		for _, f := range entry.Formats {
-			if f.VCodec != "none" && f.Height > 0 {
-				return entry
-			}
+		if hasVideoFormat(entry) {
+			return entry
 		}
 	}
 
@@ -0,0 +1,209 @@
+package downloader
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/diogocardoso/DownloaderTube/internal/i18n"
+)
+
+// MultiAudioDownloader é implementado pelos downloaders que mantêm as faixas
+// de ExtraLangs no mesmo arquivo e geram a cópia de WhatsAppCopy; os demais
+// ignoram os dois campos.
+type MultiAudioDownloader interface {
+	SupportsMultiAudio() bool
+}
+
+// audioLangs retorna os idiomas de áudio pedidos na ordem das faixas: LangCode
+// (a padrão) seguido de ExtraLangs, sem repetições.
+func (r DownloadRequest) audioLangs() []string {
+	var langs []string
+	seen := make(map[string]bool)
+	for _, code := range append([]string{r.LangCode}, r.ExtraLangs...) {
+		code = strings.TrimSpace(code)
+		if code == "" || seen[code] {
+			continue
+		}
+		seen[code] = true
+		langs = append(langs, code)
+	}
+	return langs
+}
+
+// multiAudio indica se o pedido mantém mais de uma faixa de áudio.
+func (r DownloadRequest) multiAudio() bool {
+	return r.Audio == nil && len(r.audioLangs()) > 1
+}
+
+// buildMultiAudioFormatString junta o vídeo a uma faixa de áudio por idioma,
+// na ordem pedida (exige --audio-multistreams). Se alguma faixa não existir,
+// cai no formato de um idioma só, o primeiro.
+func buildMultiAudioFormatString(height int, langs []string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "bv[height<=%d]", height)
+	for _, code := range langs {
+		fmt.Fprintf(&b, "+ba[language=%s]", code)
+	}
+	return b.String() + "/" + buildFormatString(height, langs[0])
+}
+
+// iso6392Codes converte os códigos ISO 639-1 para ISO 639-2/B, o único
+// formato de idioma que o MP4 guarda por faixa.
+var iso6392Codes = map[string]string{
+	"ar": "ara",
+	"bn": "ben",
+	"cs": "cze",
+	"da": "dan",
+	"de": "ger",
+	"el": "gre",
+	"en": "eng",
+	"es": "spa",
+	"fi": "fin",
+	"fr": "fre",
+	"he": "heb",
+	"hi": "hin",
+	"hu": "hun",
+	"id": "ind",
+	"it": "ita",
+	"ja": "jpn",
+	"ko": "kor",
+	"ms": "may",
+	"nl": "dut",
+	"no": "nor",
+	"pl": "pol",
+	"pt": "por",
+	"ro": "rum",
+	"ru": "rus",
+	"sv": "swe",
+	"ta": "tam",
+	"th": "tha",
+	"tr": "tur",
+	"uk": "ukr",
+	"vi": "vie",
+	"zh": "chi",
+}
+
+// trackLanguage retorna o idioma gravado na faixa: o código de três letras
+// (ex: "pt-BR" vira "por") ou "und" quando não houver equivalente.
+func trackLanguage(code string) string {
+	base := strings.ToLower(baseLang(code))
+	if len(base) == 3 {
+		return base
+	}
+	if iso, ok := iso6392Codes[base]; ok {
+		return iso
+	}
+	return "und"
+}
+
+// tagAudioTracks grava em cada faixa de áudio o idioma e o nome, na ordem de
+// langs, e marca só a primeira como padrão. Retorna um aviso quando vierem
+// menos faixas que o pedido ou o ffmpeg falhar; o arquivo fica como estava.
+func tagAudioTracks(ctx context.Context, videoPath string, langs []string, progress ProgressFunc) string {
+	probe, err := ProbeFile(videoPath)
+	if err != nil {
+		return i18n.Sprintf("não foi possível identificar as faixas de áudio (%v)", err)
+	}
+	var warning string
+	if n := len(probe.AudioCodecs); n < len(langs) {
+		warning = i18n.Sprintf("o vídeo veio com %d de %d faixas de áudio pedidas", n, len(langs))
+		langs = langs[:n]
+	}
+	if len(langs) == 0 {
+		return warning
+	}
+	progress.emit(ProgressEvent{Phase: PhaseAudioTracks, Percent: -1})
+
+	ext := filepath.Ext(videoPath)
+	tempOutput := strings.TrimSuffix(videoPath, ext) + " [tmp-audio]" + ext
+	args := []string{"-y", "-loglevel", "error", "-i", videoPath, "-map", "0", "-c", "copy"}
+	for i, lang := range langs {
+		disposition := "0"
+		if i == 0 {
+			disposition = "default"
+		}
+		args = append(args,
+			fmt.Sprintf("-metadata:s:a:%d", i), "language="+trackLanguage(lang),
+			fmt.Sprintf("-metadata:s:a:%d", i), "title="+resolveLangName(lang),
+			fmt.Sprintf("-disposition:a:%d", i), disposition,
+		)
+	}
+	if strings.EqualFold(ext, ".mp4") {
+		args = append(args, "-movflags", "+faststart")
+	}
+	args = append(args, tempOutput)
+
+	if err := runFFmpeg(ctx, args); err != nil {
+		if ctx.Err() != nil {
+			return ""
+		}
+		return joinWarnings(warning, i18n.Sprintf("não foi possível marcar os idiomas das faixas de áudio (%v)", err))
+	}
+	if err := os.Rename(tempOutput, videoPath); err != nil {
+		os.Remove(tempOutput)
+		return joinWarnings(warning, i18n.Sprintf("não foi possível marcar os idiomas das faixas de áudio (%v)", err))
+	}
+	return warning
+}
+
+// saveWhatsAppCopy salva ao lado de videoPath a cópia <nome>_whatsapp.mp4 só
+// com o vídeo e a faixa de áudio track, convertida para o WhatsApp se preciso.
+// Retorna o caminho da cópia ("" em caso de falha) e um aviso.
+func saveWhatsAppCopy(ctx context.Context, videoPath string, track int, progress ProgressFunc) (string, string) {
+	ext := filepath.Ext(videoPath)
+	copyPath := strings.TrimSuffix(videoPath, ext) + "_whatsapp" + ext
+	args := []string{
+		"-y", "-loglevel", "error",
+		"-i", videoPath,
+		"-map", "0:v:0",
+		"-map", "0:a:" + strconv.Itoa(track) + "?",
+		"-c", "copy",
+		"-disposition:a:0", "default",
+	}
+	if strings.EqualFold(ext, ".mp4") {
+		args = append(args, "-movflags", "+faststart")
+	}
+	args = append(args, copyPath)
+
+	progress.emit(ProgressEvent{Phase: PhaseAudioTracks, Percent: -1})
+	if err := runFFmpeg(ctx, args); err != nil {
+		if ctx.Err() != nil {
+			return "", ""
+		}
+		return "", i18n.Sprintf("não foi possível gerar a cópia para WhatsApp (%v)", err)
+	}
+
+	finalPath, warning := ensureWhatsAppCompatible(ctx, copyPath, 0, progress)
+	if ctx.Err() != nil {
+		removePartialFiles([]string{copyPath, finalPath})
+		return "", ""
+	}
+	return finalPath, warning
+}
+
+// runFFmpeg roda um ffmpeg curto (cópia de streams), removendo a saída,
+// o último argumento, em caso de erro.
+func runFFmpeg(ctx context.Context, args []string) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Minute)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "ffmpeg", args...)
+	prepareCancel(cmd)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		os.Remove(args[len(args)-1])
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("%s", msg)
+		}
+		return err
+	}
+	return nil
+}
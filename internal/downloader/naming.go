@@ -8,17 +8,19 @@ import (
 	"path/filepath"
 	"regexp"
 	"strings"
+
+	"github.com/diogocardoso/DownloaderTube/internal/i18n"
 )
 
 var nonIDCharsRegex = regexp.MustCompile(`[^a-zA-Z0-9_-]+`)
 
 func ensurePlatformFileName(filePath, platform, preferredID string) (string, string) {
 	if strings.TrimSpace(filePath) == "" {
-		return filePath, "não foi possível padronizar nome do arquivo (caminho vazio)"
+		return filePath, i18n.T("não foi possível padronizar nome do arquivo (caminho vazio)")
 	}
 
 	if _, err := os.Stat(filePath); err != nil {
-		return filePath, "não foi possível padronizar nome do arquivo (arquivo não encontrado)"
+		return filePath, i18n.T("não foi possível padronizar nome do arquivo (arquivo não encontrado)")
 	}
 
 	dir := filepath.Dir(filePath)
@@ -46,7 +48,7 @@ func ensurePlatformFileName(filePath, platform, preferredID string) (string, str
 	}
 
 	if err := os.Rename(filePath, target); err != nil {
-		return filePath, fmt.Sprintf("não foi possível renomear para padrão %s_id (%v)", platform, err)
+		return filePath, i18n.Sprintf("não foi possível renomear para padrão %s_id (%v)", platform, err)
 	}
 
 	return target, ""
@@ -0,0 +1,102 @@
+package downloader
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/diogocardoso/DownloaderTube/internal/i18n"
+)
+
+// Playlist é uma lista de vídeos, com os itens na ordem da plataforma.
+type Playlist struct {
+	ID      string          `json:"id"`
+	Title   string          `json:"title"`
+	Entries []PlaylistEntry `json:"entries"`
+}
+
+// PlaylistEntry é um item de uma playlist.
+type PlaylistEntry struct {
+	// Index é a posição na playlist, a partir de 1, contando só os vídeos
+	// disponíveis.
+	Index    int     `json:"index"`
+	ID       string  `json:"id"`
+	Title    string  `json:"title"`
+	URL      string  `json:"url"`
+	Duration float64 `json:"duration,omitempty"` // segundos; 0 quando desconhecida
+}
+
+// PlaylistLister é implementado pelos Downloaders que sabem listar os itens de
+// uma playlist sem baixá-los.
+type PlaylistLister interface {
+	GetPlaylist(ctx context.Context, url string) (*Playlist, error)
+}
+
+type ytdlpPlaylist struct {
+	ID      string `json:"id"`
+	Title   string `json:"title"`
+	Entries []struct {
+		ID       string  `json:"id"`
+		Title    string  `json:"title"`
+		URL      string  `json:"url"`
+		Duration float64 `json:"duration"`
+	} `json:"entries"`
+}
+
+// GetPlaylist lista os vídeos da playlist com --flat-playlist, que lê só a
+// página da lista e não os metadados de cada vídeo.
+func (yd *YouTubeDownloader) GetPlaylist(ctx context.Context, rawURL string) (*Playlist, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Minute)
+	defer cancel()
+
+	args := []string{"-J", "--flat-playlist", "--yes-playlist", "--no-warnings"}
+	args = yd.appendExtractorArgs(args)
+	args = append(args, proxyArgs("youtube", yd.Proxy)...)
+	output, err := runYtDlpInfo(ctx, "youtube", append(args, rawURL), yd.Cookies)
+	if err != nil {
+		return nil, i18n.Errorf("erro ao obter a playlist: %w", err)
+	}
+
+	var raw ytdlpPlaylist
+	if err := json.Unmarshal(output, &raw); err != nil {
+		return nil, i18n.Errorf("erro ao parsear a playlist: %w", err)
+	}
+
+	pl := &Playlist{ID: raw.ID, Title: raw.Title}
+	for _, e := range raw.Entries {
+		url := e.URL
+		if url == "" && e.ID != "" {
+			url = "https://www.youtube.com/watch?v=" + e.ID
+		}
+		// Vídeos removidos ou privados aparecem sem URL nem ID.
+		if url == "" {
+			continue
+		}
+		pl.Entries = append(pl.Entries, PlaylistEntry{
+			Index:    len(pl.Entries) + 1,
+			ID:       e.ID,
+			Title:    e.Title,
+			URL:      url,
+			Duration: e.Duration,
+		})
+	}
+	if len(pl.Entries) == 0 {
+		return nil, i18n.Errorf("a playlist não tem vídeos disponíveis")
+	}
+	return pl, nil
+}
+
+// prefixFileName acrescenta prefix ao início do nome do arquivo (ex: "03_"
+// para a posição na playlist), mantendo a pasta.
+func prefixFileName(filePath, prefix string) (string, string) {
+	if prefix == "" || filePath == "" {
+		return filePath, ""
+	}
+	target := filepath.Join(filepath.Dir(filePath), prefix+filepath.Base(filePath))
+	if err := os.Rename(filePath, target); err != nil {
+		return filePath, i18n.Sprintf("não foi possível numerar o arquivo (%v)", err)
+	}
+	return target, ""
+}
@@ -0,0 +1,81 @@
+package downloader
+
+import (
+	"strings"
+
+	"github.com/diogocardoso/DownloaderTube/internal/i18n"
+)
+
+// QualityPreset é uma regra nomeada de escolha de qualidade, resolvida contra
+// os formatos de cada vídeo em vez de uma altura fixa.
+type QualityPreset string
+
+const (
+	PresetBest     QualityPreset = "best"
+	PresetWorst    QualityPreset = "worst"
+	Preset720p     QualityPreset = "720p"
+	PresetWhatsApp QualityPreset = "whatsapp"
+)
+
+// QualityPresets lista os presets na ordem exibida nos menus.
+var QualityPresets = []QualityPreset{PresetBest, PresetWorst, Preset720p, PresetWhatsApp}
+
+// ParseQualityPreset reconhece o nome de um preset, sem diferenciar maiúsculas.
+func ParseQualityPreset(name string) (QualityPreset, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for _, p := range QualityPresets {
+		if string(p) == name {
+			return p, true
+		}
+	}
+	return "", false
+}
+
+// Label retorna a descrição do preset para exibição.
+func (p QualityPreset) Label() string {
+	switch p {
+	case PresetBest:
+		return i18n.T("Melhor disponível")
+	case PresetWorst:
+		return i18n.T("Menor disponível")
+	case Preset720p:
+		return i18n.T("Até 720p")
+	case PresetWhatsApp:
+		return i18n.T("Até 480p (WhatsApp)")
+	default:
+		return string(p)
+	}
+}
+
+// MaxHeight retorna a altura máxima do preset; 0 significa sem limite.
+func (p QualityPreset) MaxHeight() int {
+	switch p {
+	case Preset720p:
+		return 720
+	case PresetWhatsApp:
+		return 480
+	default:
+		return 0
+	}
+}
+
+// Resolve retorna o índice do formato escolhido pelo preset. Retorna ok=false
+// quando nenhum formato atende ao limite (ex: só há 1080p para "720p").
+func (p QualityPreset) Resolve(formats []Format) (int, bool) {
+	limit := p.MaxHeight()
+	idx := -1
+	for i, f := range formats {
+		if limit > 0 && f.Height > limit {
+			continue
+		}
+		switch {
+		case idx < 0:
+			idx = i
+		case p == PresetWorst && f.Height < formats[idx].Height:
+			idx = i
+		case p != PresetWorst && f.Height > formats[idx].Height:
+			idx = i
+		}
+	}
+	return idx, idx >= 0
+}
@@ -0,0 +1,33 @@
+package downloader
+
+import "testing"
+
+func TestQualityPresetResolve(t *testing.T) {
+	formats := []Format{{Height: 360}, {Height: 1080}, {Height: 720}, {Height: 144}}
+
+	cases := map[QualityPreset]int{
+		PresetBest:     1080,
+		PresetWorst:    144,
+		Preset720p:     720,
+		PresetWhatsApp: 360,
+	}
+	for p, want := range cases {
+		idx, ok := p.Resolve(formats)
+		if !ok || formats[idx].Height != want {
+			t.Errorf("%s: esperava %dp, veio idx=%d ok=%v", p, want, idx, ok)
+		}
+	}
+
+	if _, ok := PresetWhatsApp.Resolve([]Format{{Height: 1080}, {Height: 720}}); ok {
+		t.Errorf("whatsapp não deveria ser atendido sem formato até 480p")
+	}
+}
+
+func TestParseQualityPreset(t *testing.T) {
+	if p, ok := ParseQualityPreset(" WhatsApp "); !ok || p != PresetWhatsApp {
+		t.Errorf("ParseQualityPreset = %q, %v", p, ok)
+	}
+	if _, ok := ParseQualityPreset("4k"); ok {
+		t.Errorf("preset desconhecido deveria falhar")
+	}
+}
@@ -3,27 +3,40 @@ package downloader
 import (
 	"context"
 	"encoding/json"
-	"fmt"
 	"os/exec"
+	"strconv"
 	"strings"
 	"time"
+
+	"github.com/diogocardoso/DownloaderTube/internal/i18n"
 )
 
 // FileProbeInfo contém informações sobre as streams do arquivo baixado.
 type FileProbeInfo struct {
-	VideoCodec string
-	AudioCodec string
-	HasVideo   bool
-	HasAudio   bool
+	VideoCodec string `json:"video_codec,omitempty"`
+	AudioCodec string `json:"audio_codec,omitempty"`
+	// AudioCodecs são os codecs de todas as faixas de áudio, na ordem do arquivo.
+	AudioCodecs []string `json:"audio_codecs,omitempty"`
+	HasVideo    bool     `json:"has_video"`
+	HasAudio    bool     `json:"has_audio"`
+	Duration    float64  `json:"duration"` // em segundos; 0 quando desconhecida
 }
 
 type ffprobeOutput struct {
 	Streams []ffprobeStream `json:"streams"`
+	Format  ffprobeFormat   `json:"format"`
+}
+
+type ffprobeFormat struct {
+	Duration string `json:"duration"`
 }
 
 type ffprobeStream struct {
-	CodecType string `json:"codec_type"`
-	CodecName string `json:"codec_name"`
+	CodecType   string `json:"codec_type"`
+	CodecName   string `json:"codec_name"`
+	Disposition struct {
+		AttachedPic int `json:"attached_pic"`
+	} `json:"disposition"`
 }
 
 // ProbeFile usa ffprobe para inspecionar o arquivo e retornar os codecs de vídeo e áudio.
@@ -35,28 +48,37 @@ func ProbeFile(path string) (*FileProbeInfo, error) {
 		"-v", "quiet",
 		"-print_format", "json",
 		"-show_streams",
+		"-show_format",
 		path,
 	)
 
 	output, err := cmd.Output()
 	if err != nil {
-		return nil, fmt.Errorf("erro ao executar ffprobe: %w", err)
+		return nil, i18n.Errorf("erro ao executar ffprobe: %w", err)
 	}
 
 	var result ffprobeOutput
 	if err := json.Unmarshal(output, &result); err != nil {
-		return nil, fmt.Errorf("erro ao parsear saída do ffprobe: %w", err)
+		return nil, i18n.Errorf("erro ao parsear saída do ffprobe: %w", err)
 	}
 
 	info := &FileProbeInfo{}
+	if d, err := strconv.ParseFloat(result.Format.Duration, 64); err == nil && d > 0 {
+		info.Duration = d
+	}
 	for _, s := range result.Streams {
 		switch strings.ToLower(s.CodecType) {
 		case "video":
+			// A capa embutida em arquivos de áudio aparece como stream de vídeo.
+			if s.Disposition.AttachedPic == 1 {
+				continue
+			}
 			if !info.HasVideo {
 				info.VideoCodec = s.CodecName
 				info.HasVideo = true
 			}
 		case "audio":
+			info.AudioCodecs = append(info.AudioCodecs, s.CodecName)
 			if !info.HasAudio {
 				info.AudioCodec = s.CodecName
 				info.HasAudio = true
@@ -0,0 +1,20 @@
+//go:build !windows
+
+package downloader
+
+import (
+	"os/exec"
+	"syscall"
+	"time"
+)
+
+// prepareCancel faz o cancelamento do contexto encerrar o processo e também os
+// filhos dele (ex: ffmpeg iniciado pelo yt-dlp). O processo roda em um grupo
+// próprio para que o Ctrl+C do terminal não o mate antes da limpeza.
+func prepareCancel(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+	cmd.WaitDelay = 5 * time.Second
+}
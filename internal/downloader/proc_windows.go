@@ -0,0 +1,25 @@
+//go:build windows
+
+package downloader
+
+import (
+	"os/exec"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// prepareCancel faz o cancelamento do contexto encerrar o processo e também os
+// filhos dele (ex: ffmpeg iniciado pelo yt-dlp). O processo roda em um grupo
+// próprio para que o Ctrl+C do console não o mate antes da limpeza.
+func prepareCancel(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{CreationFlags: syscall.CREATE_NEW_PROCESS_GROUP}
+	cmd.Cancel = func() error {
+		kill := exec.Command("taskkill", "/T", "/F", "/PID", strconv.Itoa(cmd.Process.Pid))
+		if err := kill.Run(); err != nil {
+			return cmd.Process.Kill()
+		}
+		return nil
+	}
+	cmd.WaitDelay = 5 * time.Second
+}
@@ -7,67 +7,170 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 )
 
+// Phase identifica a etapa de processamento de um download.
+type Phase string
+
+const (
+	PhaseExtracting       Phase = "extracting"
+	PhaseDownloading      Phase = "downloading"
+	PhaseDownloadingVideo Phase = "downloading_video"
+	PhaseDownloadingAudio Phase = "downloading_audio"
+	// PhaseDownloadingPhotos baixa as fotos de um carrossel; StreamIndex e
+	// StreamCount indicam a foto atual.
+	PhaseDownloadingPhotos Phase = "downloading_photos"
+	PhaseMerging           Phase = "merging"
+	PhaseExtractingAudio   Phase = "extracting_audio"
+	PhaseTranscoding       Phase = "transcoding"
+	PhaseSubtitles         Phase = "subtitles"
+	// PhaseAudioTracks marca o idioma das faixas de áudio ou gera a cópia
+	// com uma faixa só.
+	PhaseAudioTracks Phase = "audio_tracks"
+	PhaseSplitting   Phase = "splitting_chapters"
+	PhaseRenaming    Phase = "renaming"
+)
+
+// ProgressEvent descreve o andamento de um download ou do processamento
+// posterior. Campos desconhecidos ficam zerados; Percent é -1 quando não há
+// como estimar o percentual.
+type ProgressEvent struct {
+	Phase       Phase         `json:"phase"`
+	Downloaded  int64         `json:"downloaded,omitempty"`
+	Total       int64         `json:"total,omitempty"`
+	Percent     float64       `json:"percent"`
+	Speed       float64       `json:"speed,omitempty"` // bytes/s no download; fator de velocidade (ex: 2.5x) na conversão
+	ETA         time.Duration `json:"-"`
+	StreamIndex int           `json:"stream_index,omitempty"` // stream atual (1-based) quando vídeo e áudio são baixados separadamente
+	StreamCount int           `json:"stream_count,omitempty"`
+	RateLimit   int64         `json:"rate_limit,omitempty"` // limite de velocidade ativo, em bytes/s
+}
+
+// ProgressFunc recebe os eventos de progresso de um download.
+type ProgressFunc func(ProgressEvent)
+
+func (f ProgressFunc) emit(ev ProgressEvent) {
+	if f != nil {
+		f(ev)
+	}
+}
+
+// ytdlpProgressTemplate faz o yt-dlp imprimir o progresso em formato fácil de
+// parsear. O percentual fica por último porque é o único campo livre.
+const ytdlpProgressTemplate = "download:__DT_PROGRESS__:%(progress.downloaded_bytes)s:%(progress.total_bytes)s:%(progress.total_bytes_estimate)s:%(progress.speed)s:%(progress.eta)s:%(info.vcodec)s:%(info.acodec)s:%(progress._percent_str)s"
+
 var (
 	progressWithSizeRegex = regexp.MustCompile(`\[download\]\s+([\d.]+)%\s+of\s+~?\s*([\d.]+)\s*([A-Za-z]+)`)
 	progressPercentRegex  = regexp.MustCompile(`([\d.]+)%`)
-	dtProgressRegex       = regexp.MustCompile(`^__DT_PROGRESS__:([^:]*):([^:]*):([^:]*):(.+)$`)
+	dtProgressRegex       = regexp.MustCompile(`^__DT_PROGRESS__:([^:]*):([^:]*):([^:]*):([^:]*):([^:]*):([^:]*):([^:]*):(.+)$`)
 )
 
 // parseProgressLine interpreta linhas de progresso do yt-dlp.
-// Quando houver tamanho total conhecido, retorna current/total em bytes.
-// Quando houver apenas percentual, retorna current=pct e total=0.
-func parseProgressLine(line string) (int64, int64, bool) {
-	if matches := dtProgressRegex.FindStringSubmatch(strings.TrimSpace(line)); len(matches) >= 5 {
-		downloaded := parseIntToken(matches[1])
-		total := parseIntToken(matches[2])
-		estimated := parseIntToken(matches[3])
-		percentToken := strings.TrimSpace(matches[4])
-
-		if total > 0 {
-			return downloaded, total, true
+// Quando houver tamanho total conhecido, preenche Downloaded/Total em bytes.
+// Quando houver apenas percentual, preenche só Percent.
+func parseProgressLine(line string) (ProgressEvent, bool) {
+	line = strings.TrimSpace(line)
+
+	if matches := dtProgressRegex.FindStringSubmatch(line); len(matches) >= 9 {
+		ev := ProgressEvent{
+			Phase:      streamPhase(matches[6], matches[7]),
+			Downloaded: parseIntToken(matches[1]),
+			Total:      parseIntToken(matches[2]),
+			Speed:      parseFloatToken(matches[4]),
+			ETA:        time.Duration(parseIntToken(matches[5])) * time.Second,
+			Percent:    -1,
+		}
+		if ev.Total <= 0 {
+			ev.Total = parseIntToken(matches[3])
 		}
-		if estimated > 0 {
-			return downloaded, estimated, true
+
+		if ev.Total > 0 {
+			ev.Percent = clampPercent(float64(ev.Downloaded) / float64(ev.Total) * 100)
+			return ev, true
 		}
-		if pct := parsePercentToken(percentToken); pct >= 0 {
-			return int64(pct), 0, true
+		if pct := parsePercentToken(matches[8]); pct >= 0 {
+			ev.Percent = float64(pct)
+			return ev, true
 		}
-		return 0, 0, false
+		return ProgressEvent{}, false
+	}
+
+	if !strings.Contains(line, "[download]") {
+		return ProgressEvent{}, false
 	}
 
 	if matches := progressWithSizeRegex.FindStringSubmatch(line); len(matches) >= 4 {
 		pct, errPct := strconv.ParseFloat(matches[1], 64)
 		totalVal, errTotal := strconv.ParseFloat(matches[2], 64)
 		if errPct != nil || errTotal != nil {
-			return 0, 0, false
+			return ProgressEvent{}, false
 		}
 
 		totalBytes := toBytes(totalVal, matches[3])
 		if totalBytes <= 0 {
-			return 0, 0, false
+			return ProgressEvent{}, false
 		}
 
-		currentBytes := int64(float64(totalBytes) * pct / 100)
-		return currentBytes, totalBytes, true
+		return ProgressEvent{
+			Phase:      PhaseDownloading,
+			Downloaded: int64(float64(totalBytes) * pct / 100),
+			Total:      totalBytes,
+			Percent:    clampPercent(pct),
+		}, true
 	}
 
 	if matches := progressPercentRegex.FindStringSubmatch(line); len(matches) >= 2 {
 		pct, err := strconv.ParseFloat(matches[1], 64)
 		if err != nil {
-			return 0, 0, false
+			return ProgressEvent{}, false
 		}
-		if pct < 0 {
-			pct = 0
-		}
-		if pct > 100 {
-			pct = 100
-		}
-		return int64(pct), 0, true
+		return ProgressEvent{Phase: PhaseDownloading, Percent: clampPercent(pct)}, true
+	}
+
+	return ProgressEvent{}, false
+}
+
+// streamPhase deduz se o yt-dlp está baixando a faixa de vídeo, a de áudio ou
+// um stream combinado a partir dos codecs do formato em download.
+func streamPhase(vcodec, acodec string) Phase {
+	hasVideo := !isNoneToken(vcodec)
+	hasAudio := !isNoneToken(acodec)
+	switch {
+	case hasVideo && !hasAudio:
+		return PhaseDownloadingVideo
+	case !hasVideo && hasAudio:
+		return PhaseDownloadingAudio
+	default:
+		return PhaseDownloading
+	}
+}
+
+func isNoneToken(raw string) bool {
+	raw = strings.TrimSpace(raw)
+	return raw == "" || strings.EqualFold(raw, "none") || strings.EqualFold(raw, "na")
+}
+
+func clampPercent(p float64) float64 {
+	if p < 0 {
+		return 0
 	}
+	if p > 100 {
+		return 100
+	}
+	return p
+}
 
-	return 0, 0, false
+func parseFloatToken(raw string) float64 {
+	raw = strings.TrimSpace(raw)
+	if isNoneToken(raw) {
+		return 0
+	}
+	f, err := strconv.ParseFloat(raw, 64)
+	if err != nil || f <= 0 {
+		return 0
+	}
+	return f
 }
 
 func parseIntToken(raw string) int64 {
@@ -0,0 +1,80 @@
+package downloader
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseProgressLineTemplate(t *testing.T) {
+	line := "__DT_PROGRESS__:5242880:10485760:NA:1048576.5:5:avc1.640028:none: 50.0%"
+
+	ev, ok := parseProgressLine(line)
+	if !ok {
+		t.Fatalf("linha de progresso não reconhecida: %q", line)
+	}
+	if ev.Phase != PhaseDownloadingVideo {
+		t.Fatalf("fase esperada %s, veio %s", PhaseDownloadingVideo, ev.Phase)
+	}
+	if ev.Downloaded != 5242880 || ev.Total != 10485760 || ev.Percent != 50 {
+		t.Fatalf("bytes/percentual inesperados: %+v", ev)
+	}
+	if ev.Speed != 1048576.5 || ev.ETA != 5*time.Second {
+		t.Fatalf("velocidade/ETA inesperados: %+v", ev)
+	}
+}
+
+func TestParseProgressLinePercentOnly(t *testing.T) {
+	ev, ok := parseProgressLine("__DT_PROGRESS__:NA:NA:NA:NA:NA:none:mp4a.40.2:  12.5%")
+	if !ok {
+		t.Fatalf("linha só com percentual deveria ser reconhecida")
+	}
+	if ev.Phase != PhaseDownloadingAudio || ev.Total != 0 || ev.Percent != 12 {
+		t.Fatalf("evento inesperado: %+v", ev)
+	}
+}
+
+func TestParseProgressLineIgnoresOtherPercentages(t *testing.T) {
+	if _, ok := parseProgressLine("[youtube] abc: 90% of players need po_token"); ok {
+		t.Fatalf("linhas fora de [download] não devem ser tratadas como progresso")
+	}
+}
+
+func TestProgressTrackerStreams(t *testing.T) {
+	var events []ProgressEvent
+	tracker := &progressTracker{progress: func(ev ProgressEvent) { events = append(events, ev) }}
+
+	lines := []string{
+		"[info] abc: Downloading 1 format(s): 137+140",
+		"[download] Destination: video.f137.mp4",
+		"__DT_PROGRESS__:10:100:NA:NA:NA:avc1:none:10%",
+		"[download] Destination: video.f140.m4a",
+		"__DT_PROGRESS__:20:40:NA:NA:NA:none:mp4a:50%",
+		`[Merger] Merging formats into "video.mp4"`,
+	}
+	for _, l := range lines {
+		tracker.handle(l)
+	}
+
+	if len(events) != 3 {
+		t.Fatalf("esperava 3 eventos, veio %d: %+v", len(events), events)
+	}
+	if events[0].StreamIndex != 1 || events[0].StreamCount != 2 || events[0].Phase != PhaseDownloadingVideo {
+		t.Fatalf("primeiro stream inesperado: %+v", events[0])
+	}
+	if events[1].StreamIndex != 2 || events[1].Phase != PhaseDownloadingAudio {
+		t.Fatalf("segundo stream inesperado: %+v", events[1])
+	}
+	if events[2].Phase != PhaseMerging {
+		t.Fatalf("esperava fase de merge, veio %+v", events[2])
+	}
+}
+
+func TestTranscodeEvent(t *testing.T) {
+	ev := transcodeEvent(30, 120, 2)
+	if ev.Percent != 25 || ev.ETA != 45*time.Second {
+		t.Fatalf("evento de conversão inesperado: %+v", ev)
+	}
+	if ev := transcodeEvent(10, 0, 1); ev.Percent != -1 {
+		t.Fatalf("sem duração o percentual deveria ser desconhecido: %+v", ev)
+	}
+}
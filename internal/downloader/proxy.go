@@ -0,0 +1,64 @@
+package downloader
+
+import (
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/diogocardoso/DownloaderTube/internal/i18n"
+)
+
+// ProxySchemes são os esquemas de proxy aceitos: os que o yt-dlp e o cliente
+// HTTP do Go (usado nas fotos de carrossel) entendem.
+var ProxySchemes = []string{"http", "https", "socks5", "socks5h"}
+
+// ParseProxy valida um proxy no formato ESQUEMA://[USUARIO:SENHA@]HOST:PORTA.
+func ParseProxy(raw string) (*url.URL, error) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return nil, i18n.Errorf("proxy inválido: %s", RedactProxy(err.Error()))
+	}
+	if !slices.Contains(ProxySchemes, strings.ToLower(u.Scheme)) {
+		return nil, i18n.Errorf("proxy inválido: use %s", strings.Join(ProxySchemes, ", "))
+	}
+	if u.Hostname() == "" {
+		return nil, i18n.Errorf("proxy inválido: falta o endereço do servidor")
+	}
+	return u, nil
+}
+
+// RedactProxy esconde usuário e senha do proxy para exibição.
+func RedactProxy(raw string) string {
+	return credentialsRegex.ReplaceAllString(raw, "${1}***@")
+}
+
+// credentialsRegex encontra o usuário (e a senha) embutidos em uma URL.
+var credentialsRegex = regexp.MustCompile(`([a-zA-Z][a-zA-Z0-9+.-]*://)[^/\s@]+@`)
+
+// proxyArgs faz o yt-dlp usar o proxy; sem proxy não adiciona argumentos.
+func proxyArgs(platform, proxy string) []string {
+	proxy = strings.TrimSpace(proxy)
+	if proxy == "" {
+		return nil
+	}
+	debugLogf("[%s] proxy enabled: %s", platform, proxy)
+	return []string{"--proxy", proxy}
+}
+
+// proxyClient retorna um cliente HTTP que passa pelo proxy, ou o cliente
+// padrão quando não há proxy.
+func proxyClient(proxy string) *http.Client {
+	if strings.TrimSpace(proxy) == "" {
+		return http.DefaultClient
+	}
+	u, err := ParseProxy(proxy)
+	if err != nil {
+		debugLogf("[proxy] ignoring invalid proxy %s: %v", proxy, err)
+		return http.DefaultClient
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = http.ProxyURL(u)
+	return &http.Client{Transport: transport}
+}
@@ -0,0 +1,79 @@
+package downloader
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/diogocardoso/DownloaderTube/internal/i18n"
+)
+
+// ParseRate interpreta um limite de velocidade como "500K", "2M" ou "1.5MB/s"
+// (múltiplos de 1024, como no yt-dlp) e retorna bytes por segundo. Vazio ou
+// "0" significa sem limite; valores enormes viram o maior limite possível.
+func ParseRate(raw string) (int64, error) {
+	s := strings.ToUpper(strings.TrimSpace(raw))
+	s = strings.TrimSuffix(s, "/S")
+	s = strings.TrimSuffix(s, "B")
+	if s == "" {
+		return 0, nil
+	}
+
+	mult := 1.0
+	switch s[len(s)-1] {
+	case 'K':
+		mult = 1 << 10
+	case 'M':
+		mult = 1 << 20
+	case 'G':
+		mult = 1 << 30
+	}
+	if mult > 1 {
+		s = s[:len(s)-1]
+	}
+	n, err := strconv.ParseFloat(s, 64)
+	if err != nil || n < 0 || math.IsNaN(n) || math.IsInf(n, 0) {
+		return 0, i18n.Errorf("limite de velocidade inválido %q (ex: 500K, 2M)", raw)
+	}
+	rate := n * mult
+	if rate >= math.MaxInt64 {
+		return math.MaxInt64, nil
+	}
+	if n > 0 && rate < 1 {
+		return 0, i18n.Errorf("limite de velocidade %q é menor que 1 byte por segundo", raw)
+	}
+	return int64(rate), nil
+}
+
+// FormatRate mostra um limite em bytes por segundo, ex: "800B/s", "500KB/s" ou
+// "2.0MB/s".
+func FormatRate(rate int64) string {
+	if rate < 1<<10 {
+		return fmt.Sprintf("%dB/s", rate)
+	}
+	if rate < 1<<20 {
+		return fmt.Sprintf("%.0fKB/s", float64(rate)/1024)
+	}
+	return fmt.Sprintf("%.1fMB/s", float64(rate)/1024/1024)
+}
+
+// ytdlpRateArgs limita a velocidade do download no yt-dlp.
+func ytdlpRateArgs(req DownloadRequest) []string {
+	if req.RateLimit <= 0 {
+		return nil
+	}
+	return []string{"--limit-rate", strconv.FormatInt(req.RateLimit, 10)}
+}
+
+// withRateLimit anota o limite ativo nos eventos, para que apareça na linha
+// de progresso.
+func (f ProgressFunc) withRateLimit(rate int64) ProgressFunc {
+	if f == nil || rate <= 0 {
+		return f
+	}
+	return func(ev ProgressEvent) {
+		ev.RateLimit = rate
+		f(ev)
+	}
+}
@@ -0,0 +1,49 @@
+package downloader
+
+import (
+	"math"
+	"testing"
+)
+
+func TestParseRate(t *testing.T) {
+	cases := map[string]int64{
+		"":        0,
+		"0":       0,
+		"2048":    2048,
+		"500K":    500 * 1024,
+		"2M":      2 * 1024 * 1024,
+		"1.5MB/s": 1572864,
+		"1g":      1 << 30,
+		"0.0":     0,
+		"1.5":     1,
+		"1e300":   math.MaxInt64,
+		"9e18G":   math.MaxInt64,
+	}
+	for raw, want := range cases {
+		got, err := ParseRate(raw)
+		if err != nil || got != want {
+			t.Errorf("ParseRate(%q) = %d, %v; esperava %d", raw, got, err, want)
+		}
+	}
+	for _, raw := range []string{"rápido", "-1M", "2T", "NaN", "Inf", "-Inf", "1e400", "0.5", "0.0001K"} {
+		if _, err := ParseRate(raw); err == nil {
+			t.Errorf("ParseRate(%q) deveria falhar", raw)
+		}
+	}
+
+	args := ytdlpRateArgs(DownloadRequest{RateLimit: 512000})
+	if len(args) != 2 || args[0] != "--limit-rate" || args[1] != "512000" {
+		t.Errorf("ytdlpRateArgs = %v", args)
+	}
+	formats := map[int64]string{
+		100:             "100B/s",
+		1023:            "1023B/s",
+		500 * 1024:      "500KB/s",
+		2 * 1024 * 1024: "2.0MB/s",
+	}
+	for rate, want := range formats {
+		if got := FormatRate(rate); got != want {
+			t.Errorf("FormatRate(%d) = %q; esperava %q", rate, got, want)
+		}
+	}
+}
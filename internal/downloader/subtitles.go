@@ -0,0 +1,367 @@
+package downloader
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/diogocardoso/DownloaderTube/internal/i18n"
+)
+
+// SubtitleTrack representa uma legenda disponível para o vídeo.
+type SubtitleTrack struct {
+	Lang string `json:"lang"`
+	Name string `json:"name"`
+	// Auto indica legenda gerada automaticamente pela plataforma.
+	Auto bool `json:"auto,omitempty"`
+}
+
+// Label descreve a legenda para exibição (ex: "English (en) [automática]").
+func (t SubtitleTrack) Label() string {
+	label := fmt.Sprintf("%s (%s)", t.Name, t.Lang)
+	if t.Auto {
+		label += " " + i18n.T("[automática]")
+	}
+	return label
+}
+
+// SubtitleMode define o que fazer com as legendas baixadas.
+type SubtitleMode string
+
+const (
+	// SubtitleEmbed embute as legendas no MP4 como faixas mov_text.
+	SubtitleEmbed SubtitleMode = "embed"
+	// SubtitleSRT salva arquivos .srt ao lado do vídeo.
+	SubtitleSRT SubtitleMode = "srt"
+)
+
+// ParseSubtitleMode reconhece o nome de um modo de legenda, sem diferenciar maiúsculas.
+func ParseSubtitleMode(name string) (SubtitleMode, bool) {
+	switch m := SubtitleMode(strings.ToLower(strings.TrimSpace(name))); m {
+	case SubtitleEmbed, SubtitleSRT:
+		return m, true
+	}
+	return "", false
+}
+
+// SubtitleOptions pede legendas junto com o vídeo.
+type SubtitleOptions struct {
+	Langs []string `json:"langs"`
+	// Auto aceita legendas automáticas quando não houver legenda manual no idioma.
+	Auto bool         `json:"auto,omitempty"`
+	Mode SubtitleMode `json:"mode"`
+}
+
+type ytdlpSubtitle struct {
+	Ext  string `json:"ext"`
+	Name string `json:"name"`
+}
+
+var (
+	subtitleFileRegex = regexp.MustCompile(`Writing video subtitles to: (.+)$`)
+	vttTimingRegex    = regexp.MustCompile(`^((?:\d+:)?\d{2}:\d{2}[.,]\d{3})\s+-->\s+((?:\d+:)?\d{2}:\d{2}[.,]\d{3})`)
+	vttTagRegex       = regexp.MustCompile(`</?([a-zA-Z]*)[^>]*>`)
+)
+
+// collectSubtitles lista as legendas manuais e automáticas que podem ser
+// convertidas para SRT. Das automáticas, mantém só as do idioma original do
+// vídeo: as traduções automáticas do YouTube somam mais de cem idiomas.
+func collectSubtitles(manual, auto map[string][]ytdlpSubtitle, videoLang string) []SubtitleTrack {
+	var tracks []SubtitleTrack
+	for lang, formats := range manual {
+		if name, ok := subtitleName(lang, formats); ok {
+			tracks = append(tracks, SubtitleTrack{Lang: lang, Name: name})
+		}
+	}
+
+	var autoTracks []SubtitleTrack
+	for lang, formats := range auto {
+		if _, ok := manual[lang]; ok {
+			continue
+		}
+		// "en-orig" é a transcrição original; "en" seria a mesma faixa reprocessada.
+		if _, ok := auto[lang+"-orig"]; ok {
+			continue
+		}
+		name, ok := subtitleName(strings.TrimSuffix(lang, "-orig"), formats)
+		if !ok {
+			continue
+		}
+		autoTracks = append(autoTracks, SubtitleTrack{Lang: lang, Name: name, Auto: true})
+	}
+
+	var original []SubtitleTrack
+	for _, t := range autoTracks {
+		if strings.HasSuffix(t.Lang, "-orig") || (videoLang != "" && baseLang(t.Lang) == baseLang(videoLang)) {
+			original = append(original, t)
+		}
+	}
+	if len(original) > 0 {
+		autoTracks = original
+	}
+	tracks = append(tracks, autoTracks...)
+
+	sort.SliceStable(tracks, func(i, j int) bool {
+		if tracks[i].Auto != tracks[j].Auto {
+			return !tracks[i].Auto
+		}
+		return tracks[i].Lang < tracks[j].Lang
+	})
+	return tracks
+}
+
+// subtitleName retorna o nome da legenda quando ela tem um formato que sabemos
+// converter (VTT ou SRT), o que também descarta o "live_chat" do YouTube.
+func subtitleName(lang string, formats []ytdlpSubtitle) (string, bool) {
+	given := ""
+	usable := false
+	for _, f := range formats {
+		if f.Ext == "vtt" || f.Ext == "srt" {
+			usable = true
+		}
+		if given == "" {
+			given = f.Name
+		}
+	}
+	if !usable {
+		return "", false
+	}
+	if name := resolveLangName(lang); name != lang || given == "" {
+		return name, true
+	}
+	return given, true
+}
+
+// ytdlpSubtitleArgs pede ao yt-dlp os arquivos de legenda; a conversão e a
+// inclusão no vídeo ficam com applySubtitles.
+func ytdlpSubtitleArgs(req DownloadRequest) []string {
+	if req.Subtitles == nil || req.Audio != nil || len(req.Subtitles.Langs) == 0 {
+		return nil
+	}
+	args := []string{"--write-subs"}
+	if req.Subtitles.Auto {
+		args = append(args, "--write-auto-subs")
+	}
+	return append(args,
+		"--sub-langs", strings.Join(req.Subtitles.Langs, ","),
+		"--sub-format", "vtt/srt",
+	)
+}
+
+// applySubtitles converte as legendas baixadas para SRT e, conforme o modo,
+// embute no vídeo ou salva ao lado dele com o mesmo nome-base
+// (<video>.<idioma>.srt). Retorna os .srt mantidos e um aviso em caso de falha.
+func applySubtitles(ctx context.Context, videoPath string, files []string, opts *SubtitleOptions, progress ProgressFunc) ([]string, string) {
+	if opts == nil || len(opts.Langs) == 0 {
+		return nil, ""
+	}
+	if len(files) == 0 {
+		return nil, i18n.T("nenhuma legenda foi baixada")
+	}
+	progress.emit(ProgressEvent{Phase: PhaseSubtitles, Percent: -1})
+
+	base := strings.TrimSuffix(videoPath, filepath.Ext(videoPath))
+	var srts, langs []string
+	var warnings []string
+	for _, f := range files {
+		// "en-orig" vira "en" no nome, que é o que os players reconhecem.
+		lang := strings.TrimSuffix(subtitleFileLang(f), "-orig")
+		data, err := os.ReadFile(f)
+		if err != nil {
+			warnings = append(warnings, i18n.Sprintf("legenda %s não encontrada", lang))
+			continue
+		}
+		if strings.EqualFold(filepath.Ext(f), ".vtt") {
+			data = VTTToSRT(data)
+		}
+
+		target := base + "." + lang + ".srt"
+		if err := os.WriteFile(target, data, 0o644); err != nil {
+			warnings = append(warnings, i18n.Sprintf("não foi possível salvar a legenda %s (%v)", lang, err))
+			continue
+		}
+		if !sameFilePath(f, target) {
+			os.Remove(f)
+		}
+		srts = append(srts, target)
+		langs = append(langs, lang)
+	}
+
+	if opts.Mode != SubtitleEmbed || len(srts) == 0 {
+		return srts, joinWarnings(warnings...)
+	}
+	// mov_text só existe em MP4; com a conversão desligada o vídeo pode ser WebM.
+	if !strings.EqualFold(filepath.Ext(videoPath), ".mp4") {
+		warnings = append(warnings, i18n.T("o vídeo não é MP4, legendas mantidas em .srt"))
+		return srts, joinWarnings(warnings...)
+	}
+
+	if err := embedSubtitles(ctx, videoPath, srts, langs); err != nil {
+		if ctx.Err() != nil {
+			return srts, ""
+		}
+		warnings = append(warnings, i18n.Sprintf("não foi possível embutir as legendas, mantidas em .srt (%v)", err))
+		return srts, joinWarnings(warnings...)
+	}
+	for _, s := range srts {
+		os.Remove(s)
+	}
+	return nil, joinWarnings(warnings...)
+}
+
+// subtitleFileLang extrai o idioma do nome gravado pelo yt-dlp ("<título>.<idioma>.vtt").
+func subtitleFileLang(path string) string {
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		return name[i+1:]
+	}
+	return "und"
+}
+
+// embedSubtitles copia os streams do vídeo e acrescenta as legendas como
+// mov_text, o formato de legenda aceito em MP4.
+func embedSubtitles(ctx context.Context, videoPath string, srts, langs []string) error {
+	tempOutput := strings.TrimSuffix(videoPath, filepath.Ext(videoPath)) + " [tmp-subs].mp4"
+
+	args := []string{"-y", "-loglevel", "error", "-i", videoPath}
+	for _, s := range srts {
+		args = append(args, "-i", s)
+	}
+	args = append(args, "-map", "0:v?", "-map", "0:a?")
+	for i := range srts {
+		args = append(args, "-map", strconv.Itoa(i+1)+":0")
+	}
+	args = append(args, "-c", "copy", "-c:s", "mov_text")
+	for i, lang := range langs {
+		args = append(args,
+			fmt.Sprintf("-metadata:s:s:%d", i), "language="+baseLang(lang),
+			fmt.Sprintf("-metadata:s:s:%d", i), "title="+resolveLangName(lang),
+		)
+	}
+	args = append(args, "-movflags", "+faststart", tempOutput)
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Minute)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "ffmpeg", args...)
+	prepareCancel(cmd)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		os.Remove(tempOutput)
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("%s", msg)
+		}
+		return err
+	}
+	if err := os.Rename(tempOutput, videoPath); err != nil {
+		os.Remove(tempOutput)
+		return err
+	}
+	return nil
+}
+
+// VTTToSRT converte uma legenda WebVTT para SubRip. Descarta cabeçalho,
+// blocos NOTE/STYLE/REGION, configurações de posição e tags (exceto <i>, <b>
+// e <u>, que o SRT aceita). Também remove a repetição das legendas
+// automáticas do YouTube, em que cada trecho repete a linha anterior.
+func VTTToSRT(data []byte) []byte {
+	type cue struct {
+		start, end string
+		lines      []string
+	}
+	var cues []cue
+
+	scanner := bufio.NewScanner(bytes.NewReader(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var cur *cue
+	skipBlock := false
+	flush := func() {
+		if cur != nil && len(cur.lines) > 0 {
+			cues = append(cues, *cur)
+		}
+		cur = nil
+	}
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		trimmed := strings.TrimSpace(line)
+
+		if trimmed == "" {
+			flush()
+			skipBlock = false
+			continue
+		}
+		if skipBlock {
+			continue
+		}
+		if cur == nil {
+			if m := vttTimingRegex.FindStringSubmatch(trimmed); m != nil {
+				cur = &cue{start: srtTimestamp(m[1]), end: srtTimestamp(m[2])}
+				continue
+			}
+			// Cabeçalho, comentários e identificadores de cue.
+			if strings.HasPrefix(trimmed, "WEBVTT") || strings.HasPrefix(trimmed, "NOTE") ||
+				strings.HasPrefix(trimmed, "STYLE") || strings.HasPrefix(trimmed, "REGION") {
+				skipBlock = true
+			}
+			continue
+		}
+		if text := cleanVTTText(trimmed); text != "" {
+			cur.lines = append(cur.lines, text)
+		}
+	}
+	flush()
+
+	var b strings.Builder
+	var prev []string
+	n := 0
+	for _, c := range cues {
+		lines := c.lines
+		if len(prev) > 0 && len(lines) > 1 && lines[0] == prev[len(prev)-1] {
+			lines = lines[1:]
+		}
+		if len(prev) > 0 && strings.Join(lines, "\n") == strings.Join(prev, "\n") {
+			continue
+		}
+		prev = c.lines
+		n++
+		fmt.Fprintf(&b, "%d\n%s --> %s\n%s\n\n", n, c.start, c.end, strings.Join(lines, "\n"))
+	}
+	return []byte(b.String())
+}
+
+// cleanVTTText remove as tags que o SRT não entende e decodifica as entidades.
+func cleanVTTText(s string) string {
+	s = vttTagRegex.ReplaceAllStringFunc(s, func(tag string) string {
+		switch strings.ToLower(vttTagRegex.FindStringSubmatch(tag)[1]) {
+		case "i", "b", "u":
+			return strings.ToLower(tag[:strings.IndexAny(tag[1:], " .>")+1]) + ">"
+		default:
+			return ""
+		}
+	})
+	s = strings.NewReplacer("&amp;", "&", "&lt;", "<", "&gt;", ">", "&nbsp;", " ", "&lrm;", "", "&rlm;", "").Replace(s)
+	return strings.TrimSpace(s)
+}
+
+// srtTimestamp converte "mm:ss.ttt" ou "hh:mm:ss.ttt" para "hh:mm:ss,ttt".
+func srtTimestamp(ts string) string {
+	ts = strings.Replace(ts, ".", ",", 1)
+	if strings.Count(ts, ":") == 1 {
+		ts = "00:" + ts
+	}
+	if i := strings.Index(ts, ":"); i == 1 {
+		ts = "0" + ts
+	}
+	return ts
+}
@@ -0,0 +1,57 @@
+package downloader
+
+import "testing"
+
+func TestVTTToSRT(t *testing.T) {
+	vtt := "\ufeffWEBVTT\nKind: captions\nLanguage: pt\n\n" +
+		"NOTE comentário que não\ndeve aparecer\n\n" +
+		"STYLE\n::cue { color: yellow }\n\n" +
+		"intro\n00:01.000 --> 00:04.500 align:start position:0%\n<v Ana>Olá, <i>mundo</i> &amp; amigos</v>\n\n" +
+		"1:02:03.250 --> 1:02:05.000\n<c.colorE5E5E5>linha com</c><00:00:01.500><c> tags</c>\n<b class=\"x\">negrito</b>\n"
+
+	want := "1\n00:00:01,000 --> 00:00:04,500\nOlá, <i>mundo</i> & amigos\n\n" +
+		"2\n01:02:03,250 --> 01:02:05,000\nlinha com tags\n<b>negrito</b>\n\n"
+	if got := string(VTTToSRT([]byte(vtt))); got != want {
+		t.Errorf("VTTToSRT:\n%q\nesperava:\n%q", got, want)
+	}
+}
+
+func TestVTTToSRTRollingCaptions(t *testing.T) {
+	// Legendas automáticas do YouTube repetem a linha anterior em cada trecho
+	// e inserem trechos de 10ms só com o texto já exibido.
+	vtt := "WEBVTT\n\n" +
+		"00:00:00.000 --> 00:00:02.000\nprimeira frase\n\n" +
+		"00:00:02.000 --> 00:00:02.010\nprimeira frase\n\n" +
+		"00:00:02.010 --> 00:00:04.000\nprimeira frase\nsegunda frase\n"
+
+	want := "1\n00:00:00,000 --> 00:00:02,000\nprimeira frase\n\n" +
+		"2\n00:00:02,010 --> 00:00:04,000\nsegunda frase\n\n"
+	if got := string(VTTToSRT([]byte(vtt))); got != want {
+		t.Errorf("VTTToSRT:\n%q\nesperava:\n%q", got, want)
+	}
+}
+
+func TestCollectSubtitles(t *testing.T) {
+	vtt := []ytdlpSubtitle{{Ext: "vtt"}}
+	manual := map[string][]ytdlpSubtitle{
+		"pt-BR":     vtt,
+		"live_chat": {{Ext: "json"}},
+	}
+	auto := map[string][]ytdlpSubtitle{
+		"pt-BR":   vtt,
+		"en":      vtt,
+		"en-orig": vtt,
+		"fr":      vtt,
+	}
+
+	tracks := collectSubtitles(manual, auto, "en")
+	if len(tracks) != 2 {
+		t.Fatalf("esperava pt-BR manual e en-orig automática, veio %+v", tracks)
+	}
+	if tracks[0].Lang != "pt-BR" || tracks[0].Auto {
+		t.Errorf("a legenda manual deveria vir primeiro: %+v", tracks[0])
+	}
+	if tracks[1].Lang != "en-orig" || !tracks[1].Auto || tracks[1].Name != "English" {
+		t.Errorf("legenda automática inesperada: %+v", tracks[1])
+	}
+}
@@ -1,29 +1,104 @@
 package downloader
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
+
+	"github.com/diogocardoso/DownloaderTube/internal/i18n"
+)
+
+var (
+	transcodeMu     sync.Mutex
+	transcodeSlots  = make(chan struct{}, 1)
+	transcodePreset = "veryfast"
+	transcodeCRF    = 23
+	transcodeOff    bool
 )
 
+// SetTranscodeConcurrency define quantas conversões com ffmpeg podem rodar ao
+// mesmo tempo, independente de quantos downloads estejam em paralelo.
+// Deve ser chamada antes de iniciar downloads.
+func SetTranscodeConcurrency(n int) {
+	if n < 1 {
+		n = 1
+	}
+	transcodeMu.Lock()
+	transcodeSlots = make(chan struct{}, n)
+	transcodeMu.Unlock()
+}
+
+// SetTranscodeEncoding define o -preset e o -crf do libx264 usados na conversão.
+// Valores vazios ou fora do intervalo 1-51 mantêm o atual.
+func SetTranscodeEncoding(preset string, crf int) {
+	transcodeMu.Lock()
+	defer transcodeMu.Unlock()
+	if preset != "" {
+		transcodePreset = preset
+	}
+	if crf >= 1 && crf <= 51 {
+		transcodeCRF = crf
+	}
+}
+
+// SetTranscodeEnabled liga ou desliga a conversão para MP4 H.264/AAC. Desligada,
+// o arquivo é mantido como o yt-dlp entregou, sem validar codecs.
+func SetTranscodeEnabled(enabled bool) {
+	transcodeMu.Lock()
+	transcodeOff = !enabled
+	transcodeMu.Unlock()
+}
+
+// acquireTranscodeSlot bloqueia até haver vaga para uma conversão (ou ctx ser
+// cancelado) e retorna a função que libera a vaga.
+func acquireTranscodeSlot(ctx context.Context) (func(), error) {
+	transcodeMu.Lock()
+	slots := transcodeSlots
+	transcodeMu.Unlock()
+
+	select {
+	case slots <- struct{}{}:
+		return func() { <-slots }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// allAudioTracks, como faixa de ensureWhatsAppCompatible, mantém todas as
+// faixas de áudio (arquivos com vários idiomas).
+const allAudioTracks = -1
+
 // ensureWhatsAppCompatible tenta garantir saída em MP4 com vídeo H.264 e áudio AAC.
+// audioTrack é a faixa de áudio mantida na conversão (0 = primeira) ou
+// allAudioTracks para converter todas.
 // Retorna um aviso quando não for possível validar/converter para o formato ideal.
-func ensureWhatsAppCompatible(filePath string) (string, string) {
+// Se ctx for cancelado, o ffmpeg é encerrado e o temporário removido.
+func ensureWhatsAppCompatible(ctx context.Context, filePath string, audioTrack int, progress ProgressFunc) (string, string) {
 	if strings.TrimSpace(filePath) == "" {
-		return filePath, "não foi possível determinar o arquivo final para validar compatibilidade com WhatsApp"
+		return filePath, i18n.T("não foi possível determinar o arquivo final para validar compatibilidade com WhatsApp")
+	}
+
+	transcodeMu.Lock()
+	off := transcodeOff
+	transcodeMu.Unlock()
+	if off {
+		return filePath, ""
 	}
 
 	if _, err := os.Stat(filePath); err != nil {
-		return filePath, "arquivo final não foi encontrado para validação de compatibilidade com WhatsApp"
+		return filePath, i18n.T("arquivo final não foi encontrado para validação de compatibilidade com WhatsApp")
 	}
 
-	needTranscode, targetPath, err := needsWhatsAppTranscode(filePath)
+	needTranscode, targetPath, duration, err := needsWhatsAppTranscode(filePath, audioTrack)
 	if err != nil {
-		return filePath, fmt.Sprintf("não foi possível validar codecs automaticamente (%v)", err)
+		return filePath, i18n.Sprintf("não foi possível validar codecs automaticamente (%v)", err)
 	}
 	if !needTranscode {
 		return targetPath, ""
@@ -31,67 +106,147 @@ func ensureWhatsAppCompatible(filePath string) (string, string) {
 
 	tempOutput := strings.TrimSuffix(targetPath, filepath.Ext(targetPath)) + " [tmp-whatsapp].mp4"
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	progress.emit(ProgressEvent{Phase: PhaseTranscoding, Percent: -1})
+	release, err := acquireTranscodeSlot(ctx)
+	if err != nil {
+		return filePath, i18n.T("conversão cancelada")
+	}
+	defer release()
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Minute)
 	defer cancel()
 
+	transcodeMu.Lock()
+	preset, crf := transcodePreset, strconv.Itoa(transcodeCRF)
+	transcodeMu.Unlock()
+
+	audioMap := "0:a?"
+	if audioTrack != allAudioTracks {
+		audioMap = fmt.Sprintf("0:a:%d?", audioTrack)
+	}
 	cmd := exec.CommandContext(ctx, "ffmpeg",
 		"-y",
+		"-loglevel", "error",
+		"-nostats",
+		"-progress", "pipe:1",
 		"-i", filePath,
 		"-map", "0:v:0",
-		"-map", "0:a:0?",
+		"-map", audioMap,
 		"-c:v", "libx264",
 		"-pix_fmt", "yuv420p",
 		"-profile:v", "high",
 		"-level", "4.1",
-		"-preset", "veryfast",
-		"-crf", "23",
+		"-preset", preset,
+		"-crf", crf,
 		"-c:a", "aac",
 		"-b:a", "128k",
 		"-movflags", "+faststart",
 		tempOutput,
 	)
+	prepareCancel(cmd)
 
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return filePath, fmt.Sprintf("falha ao converter para MP4 H.264/AAC (%s)", strings.TrimSpace(string(output)))
+	if err := runFFmpegWithProgress(cmd, duration, progress); err != nil {
+		os.Remove(tempOutput)
+		return filePath, i18n.Sprintf("falha ao converter para MP4 H.264/AAC (%v)", err)
 	}
 
 	if err := os.Remove(targetPath); err != nil && !os.IsNotExist(err) {
-		return filePath, fmt.Sprintf("arquivo convertido gerado, mas não foi possível substituir o destino (%v)", err)
+		return filePath, i18n.Sprintf("arquivo convertido gerado, mas não foi possível substituir o destino (%v)", err)
 	}
 
 	if err := os.Rename(tempOutput, targetPath); err != nil {
-		return filePath, fmt.Sprintf("arquivo convertido gerado, mas não foi possível finalizar a troca (%v)", err)
+		return filePath, i18n.Sprintf("arquivo convertido gerado, mas não foi possível finalizar a troca (%v)", err)
 	}
 
 	if sameFilePath(filePath, targetPath) {
-		return validateWhatsAppOutput(targetPath)
+		return validateWhatsAppOutput(targetPath, audioTrack)
 	}
 
 	if err := os.Remove(filePath); err != nil && !os.IsNotExist(err) {
-		return targetPath, fmt.Sprintf("arquivo convertido salvo, mas não foi possível remover o original (%v)", err)
+		return targetPath, i18n.Sprintf("arquivo convertido salvo, mas não foi possível remover o original (%v)", err)
 	}
 
-	return validateWhatsAppOutput(targetPath)
+	return validateWhatsAppOutput(targetPath, audioTrack)
 }
 
-func needsWhatsAppTranscode(filePath string) (bool, string, error) {
+func needsWhatsAppTranscode(filePath string, audioTrack int) (bool, string, float64, error) {
 	targetPath := strings.TrimSuffix(filePath, filepath.Ext(filePath)) + ".mp4"
 
 	probe, err := ProbeFile(filePath)
 	if err != nil {
-		return false, "", fmt.Errorf("erro ao validar codecs do arquivo baixado: %w", err)
+		return false, "", 0, i18n.Errorf("erro ao validar codecs do arquivo baixado: %w", err)
 	}
 
 	videoCodec := strings.ToLower(probe.VideoCodec)
-	audioCodec := strings.ToLower(probe.AudioCodec)
 	ext := strings.ToLower(filepath.Ext(filePath))
 
 	needVideo := !probe.HasVideo || videoCodec != "h264"
-	needAudio := probe.HasAudio && audioCodec != "aac"
+	needAudio := false
+	for i, codec := range probe.AudioCodecs {
+		if audioTrack == allAudioTracks || i == audioTrack {
+			needAudio = needAudio || strings.ToLower(codec) != "aac"
+		}
+	}
 	needExt := ext != ".mp4"
 
-	return needVideo || needAudio || needExt, targetPath, nil
+	return needVideo || needAudio || needExt, targetPath, probe.Duration, nil
+}
+
+// runFFmpegWithProgress executa o ffmpeg (que deve ter "-progress pipe:1") e
+// converte o out_time reportado em eventos de conversão. duration é a duração
+// da mídia em segundos, usada para calcular percentual e ETA.
+func runFFmpegWithProgress(cmd *exec.Cmd, duration float64, progress ProgressFunc) error {
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	var outTime, speed float64
+	scanner := newProgressScanner(stdout)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(strings.TrimSpace(scanner.Text()), "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "out_time_us", "out_time_ms":
+			// Apesar do nome, out_time_ms também é reportado em microssegundos.
+			if us, err := strconv.ParseFloat(value, 64); err == nil && us >= 0 {
+				outTime = us / 1e6
+			}
+		case "speed":
+			speed = parseFloatToken(strings.TrimSuffix(value, "x"))
+		case "progress":
+			progress.emit(transcodeEvent(outTime, duration, speed))
+		}
+	}
+
+	if err := cmd.Wait(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			return err
+		}
+		return fmt.Errorf("%s", msg)
+	}
+	return nil
+}
+
+func transcodeEvent(outTime, duration, speed float64) ProgressEvent {
+	ev := ProgressEvent{Phase: PhaseTranscoding, Percent: -1, Speed: speed}
+	if duration <= 0 {
+		return ev
+	}
+	ev.Percent = clampPercent(outTime / duration * 100)
+	if speed > 0 && outTime < duration {
+		ev.ETA = time.Duration((duration - outTime) / speed * float64(time.Second))
+	}
+	return ev
 }
 
 func sameFilePath(a, b string) bool {
@@ -103,13 +258,13 @@ func sameFilePath(a, b string) bool {
 	return strings.EqualFold(absA, absB)
 }
 
-func validateWhatsAppOutput(path string) (string, string) {
-	needTranscode, _, err := needsWhatsAppTranscode(path)
+func validateWhatsAppOutput(path string, audioTrack int) (string, string) {
+	needTranscode, _, _, err := needsWhatsAppTranscode(path, audioTrack)
 	if err != nil {
-		return path, fmt.Sprintf("conversão aplicada, mas não foi possível validar codecs finais (%v)", err)
+		return path, i18n.Sprintf("conversão aplicada, mas não foi possível validar codecs finais (%v)", err)
 	}
 	if needTranscode {
-		return path, "arquivo final ainda pode ser incompatível com WhatsApp (esperado MP4 H.264/AAC)"
+		return path, i18n.T("arquivo final ainda pode ser incompatível com WhatsApp (esperado MP4 H.264/AAC)")
 	}
 	return path, ""
 }
@@ -5,33 +5,54 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/url"
-	"os"
-	"os/exec"
 	"path/filepath"
 	"regexp"
 	"sort"
 	"strconv"
 	"strings"
-	"sync"
 	"time"
+
+	"github.com/diogocardoso/DownloaderTube/internal/i18n"
 )
 
-type YouTubeDownloader struct{}
+type YouTubeDownloader struct {
+	// Cookies, quando definidos, autenticam o yt-dlp no YouTube (ex: vídeos
+	// com restrição de idade ou faixas de áudio que exigem login).
+	Cookies Cookies
+	// ExtractorArgs é repassado em --extractor-args (ex: "youtube:player_client=all").
+	ExtractorArgs string
+	// Proxy é repassado ao yt-dlp em --proxy (ex: "socks5://127.0.0.1:1080").
+	Proxy string
+}
 
 func NewYouTube() *YouTubeDownloader {
 	return &YouTubeDownloader{}
 }
 
 type ytdlpInfo struct {
-	Title          string        `json:"title"`
-	DurationString string        `json:"duration_string"`
-	Formats        []ytdlpFormat `json:"formats"`
+	ID                string                     `json:"id"`
+	Title             string                     `json:"title"`
+	DurationString    string                     `json:"duration_string"`
+	Language          string                     `json:"language"`
+	Formats           []ytdlpFormat              `json:"formats"`
+	Subtitles         map[string][]ytdlpSubtitle `json:"subtitles"`
+	AutomaticCaptions map[string][]ytdlpSubtitle `json:"automatic_captions"`
+	Chapters          []ytdlpChapter             `json:"chapters"`
+	Thumbnail         string                     `json:"thumbnail"`
+	Thumbnails        []ytdlpThumbnail           `json:"thumbnails"`
+}
+
+type ytdlpThumbnail struct {
+	URL    string `json:"url"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
 }
 
 type ytdlpFormat struct {
 	FormatID       string  `json:"format_id"`
 	Ext            string  `json:"ext"`
 	Height         int     `json:"height"`
+	ABR            float64 `json:"abr"`
 	VCodec         string  `json:"vcodec"`
 	ACodec         string  `json:"acodec"`
 	Language       string  `json:"language"`
@@ -84,38 +105,14 @@ func baseLang(code string) string {
 	return strings.SplitN(code, "-", 2)[0]
 }
 
-func youtubeCookiesFromBrowser() string {
-	return strings.TrimSpace(os.Getenv("DT_COOKIES_FROM_BROWSER"))
-}
-
-func youtubeExtractorArgs() string {
-	return strings.TrimSpace(os.Getenv("DT_YT_EXTRACTOR_ARGS"))
-}
-
-func appendYouTubeExtractorArgs(args []string) []string {
-	extractorArgs := youtubeExtractorArgs()
+func (yd *YouTubeDownloader) appendExtractorArgs(args []string) []string {
+	extractorArgs := strings.TrimSpace(yd.ExtractorArgs)
 	if extractorArgs == "" {
 		return args
 	}
 	return append(args, "--extractor-args", extractorArgs)
 }
 
-func youtubeCookiesArgs() []string {
-	browser := youtubeCookiesFromBrowser()
-	if browser == "" {
-		return nil
-	}
-	return []string{"--cookies-from-browser", browser}
-}
-
-func shouldRetryWithoutCookies(err error) bool {
-	if err == nil {
-		return false
-	}
-	msg := strings.ToLower(err.Error())
-	return strings.Contains(msg, "cookie database") || strings.Contains(msg, "cookies-from-browser")
-}
-
 func collectAudioLanguages(formats []ytdlpFormat) []AudioLang {
 	langSet := make(map[string]struct{})
 	for _, f := range formats {
@@ -218,32 +215,21 @@ func normalizeLanguageCode(code string) string {
 	return strings.Join(parts, "-")
 }
 
-func (yd *YouTubeDownloader) GetVideoInfo(rawURL string) (*VideoInfo, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+func (yd *YouTubeDownloader) GetVideoInfo(ctx context.Context, rawURL string) (*VideoInfo, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
-	cookieArgs := youtubeCookiesArgs()
-	args := []string{"-j", "--no-warnings"}
-	args = appendYouTubeExtractorArgs(args)
-	args = append(args, cookieArgs...)
-	args = append(args, rawURL)
-	cmd := exec.CommandContext(ctx, "yt-dlp", args...)
-	output, err := cmd.Output()
-	if err != nil && len(cookieArgs) > 0 && shouldRetryWithoutCookies(err) {
-		debugLogf("[youtube] GetVideoInfo cookies failed, retry without cookies: %v", err)
-		retryArgs := []string{"-j", "--no-warnings"}
-		retryArgs = appendYouTubeExtractorArgs(retryArgs)
-		retryArgs = append(retryArgs, rawURL)
-		cmd = exec.CommandContext(ctx, "yt-dlp", retryArgs...)
-		output, err = cmd.Output()
-	}
+	args := []string{"-j", "--no-playlist", "--no-warnings"}
+	args = yd.appendExtractorArgs(args)
+	args = append(args, proxyArgs("youtube", yd.Proxy)...)
+	output, err := runYtDlpInfo(ctx, "youtube", append(args, rawURL), yd.Cookies)
 	if err != nil {
-		return nil, fmt.Errorf("erro ao obter info do vídeo: %w", err)
+		return nil, i18n.Errorf("erro ao obter info do vídeo: %w", err)
 	}
 
 	var info ytdlpInfo
 	if err := json.Unmarshal(output, &info); err != nil {
-		return nil, fmt.Errorf("erro ao parsear info do vídeo: %w", err)
+		return nil, i18n.Errorf("erro ao parsear info do vídeo: %w", err)
 	}
 
 	heightSeen := make(map[int]bool)
@@ -270,129 +256,77 @@ func (yd *YouTubeDownloader) GetVideoInfo(rawURL string) (*VideoInfo, error) {
 	languages := collectAudioLanguages(info.Formats)
 
 	return &VideoInfo{
-		Title:     info.Title,
-		Duration:  info.DurationString,
-		Formats:   formats,
-		Languages: languages,
+		Title:       info.Title,
+		Duration:    info.DurationString,
+		Formats:     formats,
+		Languages:   languages,
+		AudioTracks: collectAudioTracks(info.Formats),
+		Subtitles:   collectSubtitles(info.Subtitles, info.AutomaticCaptions, info.Language),
+		Chapters:    convertChapters(info.Chapters),
 	}, nil
 }
 
-func (yd *YouTubeDownloader) Download(videoURL string, height int, langCode string, dest string, progress func(current, total int64)) (DownloadResult, error) {
-	outputTemplate := filepath.Join(dest, "%(title)s.%(ext)s")
-	formatStr := buildFormatString(height, langCode)
+func (yd *YouTubeDownloader) Download(ctx context.Context, req DownloadRequest, progress ProgressFunc) (DownloadResult, error) {
 	startedAt := time.Now()
-	debugLogf("[youtube] start url=%s height=%d lang=%s format=%s", videoURL, height, langCode, formatStr)
-	cookieBrowser := youtubeCookiesFromBrowser()
-	if cookieBrowser != "" {
-		debugLogf("[youtube] cookies-from-browser enabled: %s", cookieBrowser)
-	}
-	if extractorArgs := youtubeExtractorArgs(); extractorArgs != "" {
-		debugLogf("[youtube] extractor-args enabled: %s", extractorArgs)
-	}
-
-	args := []string{
-		"-f", formatStr,
-		"--progress",
-		"--progress-template", "download:__DT_PROGRESS__:%(progress.downloaded_bytes)s:%(progress.total_bytes)s:%(progress.total_bytes_estimate)s:%(progress._percent_str)s",
-		"--merge-output-format", "mp4",
-		"--embed-thumbnail",
-		"--embed-metadata",
-		"--print", "after_move:__DT_PATH__:%(filepath)s",
-		"--print", "after_move:__DT_ID__:%(id)s",
-		"--newline",
-		"--no-warnings",
-		"-o", outputTemplate,
-	}
-	args = appendYouTubeExtractorArgs(args)
-	args = append(args, youtubeCookiesArgs()...)
-	args = append(args, videoURL)
-	cmd := exec.Command("yt-dlp", args...)
-
-	stdoutPipe, err := cmd.StdoutPipe()
-	if err != nil {
-		return DownloadResult{}, fmt.Errorf("erro ao criar pipe stdout: %w", err)
-	}
+	progress = progress.withRateLimit(req.RateLimit)
+	args := yd.downloadArgs(req)
+	// Links de vídeo aberto dentro de uma playlist (watch?v=...&list=...)
+	// baixam só o vídeo; a playlist inteira passa por GetPlaylist.
+	args = append(args, "--no-playlist", req.URL)
 
-	stderrPipe, err := cmd.StderrPipe()
+	out, err := runYtDlpDownloadWithCookies(ctx, "youtube", args, yd.Cookies, progress)
 	if err != nil {
-		return DownloadResult{}, fmt.Errorf("erro ao criar pipe stderr: %w", err)
+		return DownloadResult{}, err
 	}
 
-	if err := cmd.Start(); err != nil {
-		debugLogf("[youtube] cmd start error: %v", err)
-		return DownloadResult{}, fmt.Errorf("erro ao iniciar yt-dlp: %w", err)
-	}
-
-	var filePath string
-	var mediaID string
-	var mu sync.Mutex
-
-	parseLine := func(line string) {
-		if strings.Contains(line, "[download]") || strings.Contains(line, "ERROR") || strings.Contains(line, "WARNING") {
-			debugLogf("[youtube] line: %s", line)
-		}
-		if progress != nil {
-			if currentVal, totalVal, ok := parseProgressLine(line); ok {
-				debugLogf("[youtube] progress parsed current=%d total=%d", currentVal, totalVal)
-				progress(currentVal, totalVal)
-			}
-		}
-		if p := extractFilePath(line); p != "" {
-			debugLogf("[youtube] file path detected: %s", p)
-			mu.Lock()
-			filePath = p
-			mu.Unlock()
-		}
-		if id := extractMediaID(line); id != "" {
-			debugLogf("[youtube] media id detected: %s", id)
-			mu.Lock()
-			mediaID = id
-			mu.Unlock()
-		}
-	}
+	return finalizeDownload(ctx, "youtube", out, req, startedAt, progress)
+}
 
-	var wg sync.WaitGroup
-	wg.Add(2)
+// SupportsMultiAudio indica que Download mantém várias faixas de áudio
+// (DownloadRequest.ExtraLangs).
+func (yd *YouTubeDownloader) SupportsMultiAudio() bool {
+	return true
+}
 
-	go func() {
-		defer wg.Done()
-		scanner := newProgressScanner(stdoutPipe)
-		for scanner.Scan() {
-			parseLine(scanner.Text())
-		}
-		if err := scanner.Err(); err != nil {
-			debugLogf("[youtube] stdout scanner error: %v", err)
-		}
-	}()
+// DownloadCollection baixa os vídeos novos de um canal ou playlist (ver CollectionRequest).
+func (yd *YouTubeDownloader) DownloadCollection(ctx context.Context, req CollectionRequest, progress ProgressFunc) ([]DownloadResult, error) {
+	progress = progress.withRateLimit(req.RateLimit)
+	args := yd.downloadArgs(req.itemRequest())
+	args = append(args, ytdlpCollectionArgs(req, true)...)
+	args = append(args, req.URL)
+	return runYtDlpCollection(ctx, "youtube", args, yd.Cookies, req, progress)
+}
 
-	go func() {
-		defer wg.Done()
-		scanner := newProgressScanner(stderrPipe)
-		for scanner.Scan() {
-			parseLine(scanner.Text())
+// downloadArgs monta os argumentos do yt-dlp para req, sem a URL.
+func (yd *YouTubeDownloader) downloadArgs(req DownloadRequest) []string {
+	outputTemplate := filepath.Join(req.Dest, "%(title)s.%(ext)s")
+	if yd.ExtractorArgs != "" {
+		debugLogf("[youtube] extractor-args enabled: %s", yd.ExtractorArgs)
+	}
+
+	var args []string
+	if req.Audio != nil {
+		formatStr := buildAudioFormatString(req.Audio.Bitrate, req.LangCode)
+		debugLogf("[youtube] start audio url=%s audio=%s bitrate=%d lang=%s format=%s", req.URL, req.Audio.Format, req.Audio.Bitrate, req.LangCode, formatStr)
+		args = ytdlpAudioArgs(formatStr, outputTemplate, req.Audio.Format)
+	} else {
+		formatStr := buildFormatString(req.Height, req.LangCode)
+		if req.multiAudio() {
+			formatStr = buildMultiAudioFormatString(req.Height, req.audioLangs())
 		}
-		if err := scanner.Err(); err != nil {
-			debugLogf("[youtube] stderr scanner error: %v", err)
+		debugLogf("[youtube] start url=%s height=%d lang=%s extra=%v format=%s", req.URL, req.Height, req.LangCode, req.ExtraLangs, formatStr)
+		args = ytdlpDownloadArgs(formatStr, outputTemplate)
+		args = append(args, "--embed-thumbnail")
+		if req.multiAudio() {
+			args = append(args, "--audio-multistreams")
 		}
-	}()
-
-	wg.Wait()
-
-	if err := cmd.Wait(); err != nil {
-		debugLogf("[youtube] cmd wait error: %v", err)
-		return DownloadResult{}, fmt.Errorf("erro durante download: %w", err)
 	}
-
-	resolvedPath := resolveDownloadedFile(filePath, dest, mediaID, startedAt)
-	debugLogf("[youtube] resolved path parsed=%s resolved=%s", filePath, resolvedPath)
-	finalPath, warning := ensureWhatsAppCompatible(resolvedPath)
-	namedPath, nameWarning := ensurePlatformFileName(finalPath, "youtube", mediaID)
-	debugLogf("[youtube] done filePath=%s finalPath=%s namedPath=%s mediaID=%s warning=%s nameWarning=%s", resolvedPath, finalPath, namedPath, mediaID, warning, nameWarning)
-
-	return DownloadResult{
-		FilePath:             namedPath,
-		CompatibilityWarning: joinWarnings(warning, nameWarning),
-	}, nil
+	args = append(args, ytdlpSubtitleArgs(req)...)
+	args = append(args, ytdlpClipArgs(req)...)
+	args = append(args, ytdlpChapterArgs(req)...)
+	args = append(args, ytdlpRateArgs(req)...)
+	args = yd.appendExtractorArgs(args)
+	return append(args, proxyArgs("youtube", yd.Proxy)...)
 }
 
 func buildFormatString(height int, langCode string) string {
@@ -51,28 +51,15 @@ func assertOrder(t *testing.T, src string, parts ...string) {
 	}
 }
 
-func TestYouTubeCookiesArgs(t *testing.T) {
-	t.Setenv("DT_COOKIES_FROM_BROWSER", "")
-	if got := youtubeCookiesArgs(); len(got) != 0 {
-		t.Fatalf("esperava sem args quando env vazia, veio: %v", got)
-	}
-
-	t.Setenv("DT_COOKIES_FROM_BROWSER", "chrome")
-	got := youtubeCookiesArgs()
-	if len(got) != 2 || got[0] != "--cookies-from-browser" || got[1] != "chrome" {
-		t.Fatalf("args inesperados: %v", got)
-	}
-}
-
 func TestYouTubeExtractorArgs(t *testing.T) {
-	t.Setenv("DT_YT_EXTRACTOR_ARGS", "")
-	args := appendYouTubeExtractorArgs([]string{"-j"})
+	yd := &YouTubeDownloader{}
+	args := yd.appendExtractorArgs([]string{"-j"})
 	if len(args) != 1 || args[0] != "-j" {
-		t.Fatalf("nao deveria alterar args sem env: %v", args)
+		t.Fatalf("nao deveria alterar args sem extractor args: %v", args)
 	}
 
-	t.Setenv("DT_YT_EXTRACTOR_ARGS", "youtube:player_client=all;formats=missing_pot")
-	args = appendYouTubeExtractorArgs([]string{"-j"})
+	yd.ExtractorArgs = "youtube:player_client=all;formats=missing_pot"
+	args = yd.appendExtractorArgs([]string{"-j"})
 	if len(args) != 3 || args[1] != "--extractor-args" || args[2] != "youtube:player_client=all;formats=missing_pot" {
 		t.Fatalf("args extractor inesperados: %v", args)
 	}
@@ -126,3 +113,36 @@ func TestCollectAudioLanguagesFallsBackToFormatAndURL(t *testing.T) {
 		}
 	}
 }
+
+func TestBuildMultiAudioFormatString(t *testing.T) {
+	got := buildMultiAudioFormatString(720, []string{"en", "pt-BR"})
+
+	if !strings.HasPrefix(got, "bv[height<=720]+ba[language=en]+ba[language=pt-BR]/") {
+		t.Fatalf("deveria juntar uma faixa por idioma, na ordem pedida: %q", got)
+	}
+	assertOrder(t, got, "+ba[language=pt-BR]/", "b[language=en][height<=720]", "ba[ext=m4a]")
+}
+
+func TestAudioLangs(t *testing.T) {
+	req := DownloadRequest{LangCode: "en", ExtraLangs: []string{"pt-BR", "en", " ", "es"}}
+	got := req.audioLangs()
+	if strings.Join(got, ",") != "en,pt-BR,es" {
+		t.Fatalf("idiomas inesperados: %v", got)
+	}
+	if !req.multiAudio() {
+		t.Fatal("deveria manter várias faixas")
+	}
+	req.Audio = &AudioOptions{Format: AudioMP3}
+	if req.multiAudio() {
+		t.Fatal("o modo somente áudio baixa uma faixa só")
+	}
+}
+
+func TestTrackLanguage(t *testing.T) {
+	cases := map[string]string{"pt-BR": "por", "en": "eng", "fil": "fil", "xx": "und"}
+	for code, want := range cases {
+		if got := trackLanguage(code); got != want {
+			t.Errorf("trackLanguage(%q) = %q, esperava %q", code, got, want)
+		}
+	}
+}
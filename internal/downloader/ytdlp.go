@@ -0,0 +1,364 @@
+package downloader
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/diogocardoso/DownloaderTube/internal/i18n"
+)
+
+var (
+	formatsRegex   = regexp.MustCompile(`^\[info\] [^:]+: Downloading \d+ format\(s\): (\S+)`)
+	thumbnailRegex = regexp.MustCompile(`Writing video thumbnail .* to: (.+)$`)
+	extractRegex   = regexp.MustCompile(`^\[ExtractAudio\] Destination: (.+)$`)
+	// indexPrintRegex casa a posição do item impressa com ytdlpIndexPrintArgs.
+	indexPrintRegex = regexp.MustCompile(`^__DT_INDEX__:(\d+)$`)
+)
+
+// ytdlpOutput guarda o que foi capturado da saída do yt-dlp durante um download.
+type ytdlpOutput struct {
+	FilePath  string
+	MediaID   string
+	Subtitles []string
+	Chapters  []Chapter
+	// Items são os arquivos concluídos, na ordem, quando uma execução baixa
+	// vários itens (canais, perfis, carrosséis).
+	Items []ytdlpItem
+
+	// artifacts são arquivos intermediários gravados pelo yt-dlp, removidos se
+	// o download for cancelado.
+	artifacts []string
+	// cookieFailed indica que o yt-dlp não conseguiu ler os cookies.
+	cookieFailed bool
+}
+
+// ytdlpItem é um arquivo concluído, identificado pelos templates
+// __DT_PATH__/__DT_ID__ e, quando pedido, __DT_INDEX__.
+type ytdlpItem struct {
+	FilePath string
+	MediaID  string
+	// Index é a posição do item na lista (playlist_index); 0 quando não foi impressa.
+	Index int
+}
+
+// ytdlpIndexPrintArgs fazem o yt-dlp imprimir a posição de cada item concluído.
+var ytdlpIndexPrintArgs = []string{"--print", "after_move:__DT_INDEX__:%(playlist_index)s"}
+
+// ytdlpDownloadArgs são os argumentos comuns de download a todas as plataformas.
+func ytdlpDownloadArgs(formatStr, outputTemplate string) []string {
+	return []string{
+		"-f", formatStr,
+		"--progress",
+		"--progress-template", ytdlpProgressTemplate,
+		"--merge-output-format", "mp4",
+		"--embed-metadata",
+		"--print", "after_move:__DT_PATH__:%(filepath)s",
+		"--print", "after_move:__DT_ID__:%(id)s",
+		"--newline",
+		"--no-warnings",
+		"-o", outputTemplate,
+	}
+}
+
+// progressTracker converte as linhas do yt-dlp em ProgressEvent, acompanhando
+// qual stream (vídeo/áudio) está sendo baixado. As linhas chegam de stdout e
+// stderr em goroutines separadas, por isso o acesso é serializado.
+type progressTracker struct {
+	mu          sync.Mutex
+	progress    ProgressFunc
+	streamIndex int
+	streamCount int
+}
+
+func (t *progressTracker) handle(line string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	trimmed := strings.TrimSpace(line)
+	if m := formatsRegex.FindStringSubmatch(trimmed); len(m) >= 2 {
+		t.streamCount = strings.Count(m[1], "+") + 1
+		t.streamIndex = 0
+		return
+	}
+	if destRegex.MatchString(line) {
+		t.streamIndex++
+		return
+	}
+	if mergerRegex.MatchString(line) {
+		t.progress.emit(ProgressEvent{Phase: PhaseMerging, Percent: -1})
+		return
+	}
+	if extractRegex.MatchString(trimmed) {
+		t.progress.emit(ProgressEvent{Phase: PhaseExtractingAudio, Percent: -1})
+		return
+	}
+
+	if ev, ok := parseProgressLine(line); ok {
+		if t.streamCount > 1 {
+			ev.StreamIndex = max(t.streamIndex, 1)
+			ev.StreamCount = t.streamCount
+		}
+		t.progress.emit(ev)
+	}
+}
+
+// runYtDlpDownload executa o yt-dlp, repassa o progresso e captura o caminho
+// final e o ID da mídia impressos pelos templates __DT_PATH__/__DT_ID__.
+// Se ctx for cancelado, o yt-dlp (e seus filhos) é encerrado e os arquivos
+// parciais são removidos antes de retornar.
+func runYtDlpDownload(ctx context.Context, platform string, args []string, progress ProgressFunc) (ytdlpOutput, error) {
+	progress.emit(ProgressEvent{Phase: PhaseExtracting, Percent: -1})
+
+	cmd := exec.CommandContext(ctx, "yt-dlp", args...)
+	prepareCancel(cmd)
+
+	stdoutPipe, err := cmd.StdoutPipe()
+	if err != nil {
+		return ytdlpOutput{}, i18n.Errorf("erro ao criar pipe stdout: %w", err)
+	}
+
+	stderrPipe, err := cmd.StderrPipe()
+	if err != nil {
+		return ytdlpOutput{}, i18n.Errorf("erro ao criar pipe stderr: %w", err)
+	}
+
+	if err := cmd.Start(); err != nil {
+		debugLogf("[%s] cmd start error: %v", platform, err)
+		return ytdlpOutput{}, i18n.Errorf("erro ao iniciar yt-dlp: %w", err)
+	}
+
+	var out ytdlpOutput
+	var mu sync.Mutex
+	tracker := &progressTracker{progress: progress}
+
+	parseLine := func(line string) {
+		if strings.Contains(line, "[download]") || strings.Contains(line, "ERROR") || strings.Contains(line, "WARNING") {
+			debugLogf("[%s] line: %s", platform, line)
+		}
+		if strings.Contains(line, "ERROR") && isCookieError(line) {
+			mu.Lock()
+			out.cookieFailed = true
+			mu.Unlock()
+		}
+		tracker.handle(line)
+		if artifact := extractArtifact(line); artifact != "" {
+			mu.Lock()
+			out.artifacts = append(out.artifacts, artifact)
+			mu.Unlock()
+		}
+		if m := subtitleFileRegex.FindStringSubmatch(line); len(m) >= 2 {
+			mu.Lock()
+			out.Subtitles = append(out.Subtitles, strings.TrimSpace(m[1]))
+			mu.Unlock()
+		}
+		if chapters, ok := parseChaptersLine(line); ok {
+			mu.Lock()
+			out.Chapters = chapters
+			mu.Unlock()
+		}
+		if m := printRegex.FindStringSubmatch(strings.TrimSpace(line)); len(m) >= 2 {
+			mu.Lock()
+			out.Items = append(out.Items, ytdlpItem{FilePath: strings.TrimSpace(m[1])})
+			mu.Unlock()
+		}
+		if m := indexPrintRegex.FindStringSubmatch(strings.TrimSpace(line)); len(m) >= 2 {
+			n, _ := strconv.Atoi(m[1])
+			mu.Lock()
+			if k := len(out.Items); k > 0 {
+				out.Items[k-1].Index = n
+			}
+			mu.Unlock()
+		}
+		if p := extractFilePath(line); p != "" {
+			debugLogf("[%s] file path detected: %s", platform, p)
+			mu.Lock()
+			out.FilePath = p
+			mu.Unlock()
+		}
+		if id := extractMediaID(line); id != "" {
+			debugLogf("[%s] media id detected: %s", platform, id)
+			mu.Lock()
+			out.MediaID = id
+			if n := len(out.Items); n > 0 && out.Items[n-1].MediaID == "" {
+				out.Items[n-1].MediaID = id
+			}
+			mu.Unlock()
+		}
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+
+	go func() {
+		defer wg.Done()
+		scanner := newProgressScanner(stdoutPipe)
+		for scanner.Scan() {
+			parseLine(scanner.Text())
+		}
+		if err := scanner.Err(); err != nil {
+			debugLogf("[%s] stdout scanner error: %v", platform, err)
+		}
+	}()
+
+	go func() {
+		defer wg.Done()
+		scanner := newProgressScanner(stderrPipe)
+		for scanner.Scan() {
+			parseLine(scanner.Text())
+		}
+		if err := scanner.Err(); err != nil {
+			debugLogf("[%s] stderr scanner error: %v", platform, err)
+		}
+	}()
+
+	wg.Wait()
+
+	err = cmd.Wait()
+	if ctx.Err() != nil {
+		partial := out.partialArtifacts()
+		debugLogf("[%s] canceled, removing partial files: %v", platform, partial)
+		removePartialFiles(partial)
+		return ytdlpOutput{}, canceledError(ctx)
+	}
+	if err != nil {
+		debugLogf("[%s] cmd wait error: %v", platform, err)
+		return out, i18n.Errorf("erro durante download: %w", err)
+	}
+
+	return out, nil
+}
+
+// partialArtifacts são os artefatos que não pertencem a um item concluído.
+// Itens concluídos já podem estar no --download-archive e não são apagados.
+func (o ytdlpOutput) partialArtifacts() []string {
+	done := make(map[string]bool, len(o.Items))
+	for _, it := range o.Items {
+		done[it.FilePath] = true
+	}
+	var partial []string
+	for _, a := range o.artifacts {
+		if !done[a] {
+			partial = append(partial, a)
+		}
+	}
+	return partial
+}
+
+// extractArtifact identifica arquivos gravados pelo yt-dlp durante o download:
+// destinos de cada stream, saída do merge, áudio extraído, thumbnails e legendas.
+func extractArtifact(line string) string {
+	if m := destRegex.FindStringSubmatch(line); len(m) >= 2 {
+		return strings.TrimSpace(m[1])
+	}
+	if m := mergerRegex.FindStringSubmatch(line); len(m) >= 2 {
+		return m[1]
+	}
+	if m := thumbnailRegex.FindStringSubmatch(line); len(m) >= 2 {
+		return strings.TrimSpace(m[1])
+	}
+	if m := extractRegex.FindStringSubmatch(strings.TrimSpace(line)); len(m) >= 2 {
+		return m[1]
+	}
+	if m := subtitleFileRegex.FindStringSubmatch(line); len(m) >= 2 {
+		return strings.TrimSpace(m[1])
+	}
+	return ""
+}
+
+// finalizeDownload localiza o arquivo baixado, garante compatibilidade com o
+// WhatsApp (exceto no modo somente áudio), marca os idiomas das faixas de
+// áudio, aplica o padrão de nome <plataforma>_<id>, gera a cópia para WhatsApp
+// com uma faixa só, trata as legendas pedidas e separa os capítulos. Retorna
+// erro apenas quando ctx é cancelado durante a conversão.
+func finalizeDownload(ctx context.Context, platform string, out ytdlpOutput, req DownloadRequest, startedAt time.Time, progress ProgressFunc) (DownloadResult, error) {
+	resolvedPath := resolveDownloadedFile(out.FilePath, req.Dest, out.MediaID, startedAt)
+	debugLogf("[%s] resolved path parsed=%s resolved=%s", platform, out.FilePath, resolvedPath)
+	finalPath, warning := resolvedPath, ""
+	if req.Audio == nil {
+		track := 0
+		if req.multiAudio() {
+			track = allAudioTracks
+		}
+		finalPath, warning = ensureWhatsAppCompatible(ctx, resolvedPath, track, progress)
+	}
+	if ctx.Err() != nil {
+		debugLogf("[%s] canceled during transcode, keeping %s", platform, finalPath)
+		return DownloadResult{}, canceledError(ctx)
+	}
+	if req.multiAudio() {
+		warning = joinWarnings(warning, tagAudioTracks(ctx, finalPath, req.audioLangs(), progress))
+		if ctx.Err() != nil {
+			return DownloadResult{}, canceledError(ctx)
+		}
+	}
+
+	progress.emit(ProgressEvent{Phase: PhaseRenaming, Percent: -1})
+	nameID := out.MediaID
+	if req.Clip != nil && nameID != "" {
+		nameID += "_" + req.Clip.fileSuffix()
+	}
+	namedPath, nameWarning := ensurePlatformFileName(finalPath, platform, nameID)
+	if req.FilePrefix != "" {
+		var prefixWarning string
+		namedPath, prefixWarning = prefixFileName(namedPath, req.FilePrefix)
+		nameWarning = joinWarnings(nameWarning, prefixWarning)
+	}
+	debugLogf("[%s] done filePath=%s finalPath=%s namedPath=%s mediaID=%s warning=%s nameWarning=%s", platform, resolvedPath, finalPath, namedPath, out.MediaID, warning, nameWarning)
+
+	var whatsAppFile string
+	if req.multiAudio() && req.WhatsAppCopy {
+		var copyWarning string
+		whatsAppFile, copyWarning = saveWhatsAppCopy(ctx, namedPath, 0, progress)
+		if ctx.Err() != nil {
+			return DownloadResult{}, canceledError(ctx)
+		}
+		warning = joinWarnings(warning, copyWarning)
+	}
+
+	var subtitles []string
+	var subtitleWarning string
+	if req.Audio == nil {
+		subtitles, subtitleWarning = applySubtitles(ctx, namedPath, out.Subtitles, req.Subtitles, progress)
+		if ctx.Err() != nil {
+			removePartialFiles(append(out.Subtitles, subtitles...))
+			return DownloadResult{}, canceledError(ctx)
+		}
+	}
+
+	var files []string
+	var chapterWarning string
+	if req.Chapters != "" && req.Audio == nil && req.Clip == nil {
+		if len(out.Chapters) == 0 {
+			chapterWarning = i18n.T("o vídeo não tem capítulos; mantido o arquivo inteiro")
+		} else {
+			files, chapterWarning = splitChapters(ctx, namedPath, out.Chapters, progress)
+			if ctx.Err() != nil {
+				return DownloadResult{}, canceledError(ctx)
+			}
+			// Só descarta o vídeo inteiro quando todos os capítulos foram gerados.
+			if req.Chapters == ChaptersSplit && len(files) == len(out.Chapters) {
+				if err := os.Remove(namedPath); err != nil {
+					debugLogf("[%s] remove full video failed: %v", platform, err)
+				}
+				namedPath = files[0]
+			}
+		}
+	}
+
+	return DownloadResult{
+		FilePath:             namedPath,
+		MediaID:              out.MediaID,
+		CompatibilityWarning: joinWarnings(warning, nameWarning),
+		Subtitles:            subtitles,
+		SubtitleWarning:      subtitleWarning,
+		Files:                files,
+		ChapterWarning:       chapterWarning,
+		WhatsAppFile:         whatsAppFile,
+	}, nil
+}
@@ -0,0 +1,239 @@
+package history
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/diogocardoso/DownloaderTube/internal/i18n"
+)
+
+// Entry registra uma tentativa de download, bem-sucedida ou não.
+type Entry struct {
+	ID         string    `json:"id"`
+	URL        string    `json:"url"`
+	Platform   string    `json:"platform"`
+	MediaID    string    `json:"media_id,omitempty"`
+	Title      string    `json:"title,omitempty"`
+	Height     int       `json:"height"`
+	LangCode   string    `json:"lang_code,omitempty"`
+	FilePath   string    `json:"file_path,omitempty"`
+	Warnings   []string  `json:"warnings,omitempty"`
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+	Success    bool      `json:"success"`
+	Error      string    `json:"error,omitempty"`
+	// RetryOf aponta para o ID da tentativa que falhou, quando esta for uma nova tentativa.
+	RetryOf string `json:"retry_of,omitempty"`
+	// AudioFormat e AudioBitrate são preenchidos nos downloads somente de áudio.
+	AudioFormat  string `json:"audio_format,omitempty"`
+	AudioBitrate int    `json:"audio_bitrate,omitempty"`
+	// SubtitleLangs, SubtitleAuto e SubtitleMode registram as legendas pedidas.
+	SubtitleLangs []string `json:"subtitle_langs,omitempty"`
+	SubtitleAuto  bool     `json:"subtitle_auto,omitempty"`
+	SubtitleMode  string   `json:"subtitle_mode,omitempty"`
+	// ClipStart e ClipEnd limitam o download a um trecho, em segundos (fim 0 = até o fim).
+	ClipStart float64 `json:"clip_start,omitempty"`
+	ClipEnd   float64 `json:"clip_end,omitempty"`
+	// ChapterMode registra se o vídeo foi separado por capítulos; Files lista
+	// os arquivos dos capítulos.
+	ChapterMode string   `json:"chapter_mode,omitempty"`
+	Files       []string `json:"files,omitempty"`
+	// Items registra as posições escolhidas de um carrossel do Instagram;
+	// vazio quando todos os itens foram pedidos.
+	Items []int `json:"items,omitempty"`
+	// ExtraLangs são as faixas de áudio mantidas no arquivo além de LangCode, a
+	// padrão; WhatsAppCopy indica que foi pedida a cópia com uma faixa só.
+	ExtraLangs   []string `json:"extra_langs,omitempty"`
+	WhatsAppCopy bool     `json:"whatsapp_copy,omitempty"`
+}
+
+type fileFormat struct {
+	Version int     `json:"version"`
+	Entries []Entry `json:"entries"`
+}
+
+const fileVersion = 1
+
+// Store persiste o histórico em um arquivo JSON. É seguro para uso concorrente
+// dentro do mesmo processo (ex: vários workers da fila gravando ao mesmo tempo).
+type Store struct {
+	path string
+	mu   sync.Mutex
+	seq  int64
+}
+
+// DefaultPath retorna o caminho padrão do histórico no diretório de configuração do usuário.
+func DefaultPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", i18n.Errorf("não foi possível determinar diretório de configuração: %w", err)
+	}
+	return filepath.Join(dir, "DownloaderTube", "history.json"), nil
+}
+
+// Open cria um Store para o arquivo informado. O arquivo é criado no primeiro Add.
+func Open(path string) *Store {
+	return &Store{path: path}
+}
+
+// Path retorna o caminho do arquivo de histórico.
+func (s *Store) Path() string {
+	return s.path
+}
+
+// Add grava uma nova entrada e retorna a entrada com o ID preenchido.
+func (s *Store) Add(e Entry) (Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := s.load()
+	if err != nil {
+		return e, err
+	}
+
+	s.seq++
+	e.ID = strconv.FormatInt(time.Now().UnixNano(), 36) + strconv.FormatInt(s.seq, 36)
+	entries = append(entries, e)
+
+	return e, s.save(entries)
+}
+
+// List retorna todas as entradas, da mais recente para a mais antiga.
+func (s *Store) List() ([]Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].StartedAt.After(entries[j].StartedAt)
+	})
+	return entries, nil
+}
+
+// Search retorna as entradas cujo título, URL, caminho, plataforma ou ID de mídia
+// contenham o termo (sem diferenciar maiúsculas), da mais recente para a mais antiga.
+func (s *Store) Search(term string) ([]Entry, error) {
+	entries, err := s.List()
+	if err != nil {
+		return nil, err
+	}
+
+	term = strings.ToLower(strings.TrimSpace(term))
+	if term == "" {
+		return entries, nil
+	}
+
+	var out []Entry
+	for _, e := range entries {
+		haystack := strings.ToLower(strings.Join([]string{e.Title, e.URL, e.FilePath, e.Platform, e.MediaID}, " "))
+		if strings.Contains(haystack, term) {
+			out = append(out, e)
+		}
+	}
+	return out, nil
+}
+
+// PendingFailures filtra as falhas iniciadas a partir de since que ainda não
+// tiveram um download bem-sucedido posterior da mesma URL. Retorna uma entrada
+// por URL (a falha mais recente), da mais recente para a mais antiga.
+func PendingFailures(entries []Entry, since time.Time) []Entry {
+	lastSuccess := make(map[string]time.Time)
+	for _, e := range entries {
+		if e.Success && e.StartedAt.After(lastSuccess[e.URL]) {
+			lastSuccess[e.URL] = e.StartedAt
+		}
+	}
+
+	sorted := append([]Entry(nil), entries...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].StartedAt.After(sorted[j].StartedAt)
+	})
+
+	seen := make(map[string]bool)
+	var out []Entry
+	for _, e := range sorted {
+		if e.Success || e.StartedAt.Before(since) || seen[e.URL] {
+			continue
+		}
+		seen[e.URL] = true
+		if last, found := lastSuccess[e.URL]; found && last.After(e.StartedAt) {
+			continue
+		}
+		out = append(out, e)
+	}
+	return out
+}
+
+// Delete remove a entrada com o ID informado.
+func (s *Store) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := s.load()
+	if err != nil {
+		return err
+	}
+
+	kept := entries[:0]
+	found := false
+	for _, e := range entries {
+		if e.ID == id {
+			found = true
+			continue
+		}
+		kept = append(kept, e)
+	}
+	if !found {
+		return i18n.Errorf("registro %s não encontrado no histórico", id)
+	}
+	return s.save(kept)
+}
+
+func (s *Store) load() ([]Entry, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, i18n.Errorf("erro ao ler histórico: %w", err)
+	}
+
+	var f fileFormat
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, i18n.Errorf("erro ao parsear histórico: %w", err)
+	}
+	return f.Entries, nil
+}
+
+// save grava em arquivo temporário e renomeia, para não corromper o histórico
+// se o processo for interrompido no meio da escrita.
+func (s *Store) save(entries []Entry) error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return i18n.Errorf("erro ao criar diretório do histórico: %w", err)
+	}
+
+	data, err := json.MarshalIndent(fileFormat{Version: fileVersion, Entries: entries}, "", "  ")
+	if err != nil {
+		return i18n.Errorf("erro ao serializar histórico: %w", err)
+	}
+
+	tmpPath := s.path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0o644); err != nil {
+		return i18n.Errorf("erro ao gravar histórico: %w", err)
+	}
+	if err := os.Rename(tmpPath, s.path); err != nil {
+		os.Remove(tmpPath)
+		return i18n.Errorf("erro ao salvar histórico: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package history
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestStoreAddListSearchDelete(t *testing.T) {
+	s := Open(filepath.Join(t.TempDir(), "sub", "history.json"))
+
+	base := time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)
+	first, err := s.Add(Entry{URL: "https://youtu.be/a", Platform: "youtube", Title: "Aula de Go", StartedAt: base, Success: true})
+	if err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if _, err := s.Add(Entry{URL: "https://fb.watch/b", Platform: "facebook", StartedAt: base.Add(time.Minute), Error: "falhou"}); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+
+	all, err := s.List()
+	if err != nil || len(all) != 2 {
+		t.Fatalf("List = %d entradas, err=%v", len(all), err)
+	}
+	if all[0].Platform != "facebook" {
+		t.Fatalf("esperava a entrada mais recente primeiro, veio %+v", all[0])
+	}
+
+	found, err := s.Search("aula")
+	if err != nil || len(found) != 1 || found[0].ID != first.ID {
+		t.Fatalf("Search inesperado: %+v err=%v", found, err)
+	}
+
+	if err := s.Delete(first.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if err := s.Delete(first.ID); err == nil {
+		t.Fatalf("Delete repetido deveria falhar")
+	}
+
+	all, _ = s.List()
+	if len(all) != 1 || all[0].Platform != "facebook" {
+		t.Fatalf("esperava restar apenas facebook, veio %+v", all)
+	}
+}
+
+func TestPendingFailures(t *testing.T) {
+	today := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	entries := []Entry{
+		{ID: "1", URL: "a", StartedAt: today.Add(1 * time.Hour)},
+		{ID: "2", URL: "a", StartedAt: today.Add(2 * time.Hour), Success: true},
+		{ID: "3", URL: "b", StartedAt: today.Add(3 * time.Hour)},
+		{ID: "4", URL: "b", StartedAt: today.Add(4 * time.Hour)},
+		{ID: "5", URL: "c", StartedAt: today.Add(-2 * time.Hour)},
+		{ID: "6", URL: "d", StartedAt: today.Add(5 * time.Hour)},
+	}
+
+	got := PendingFailures(entries, today)
+	if len(got) != 2 {
+		t.Fatalf("esperava 2 falhas pendentes, veio %+v", got)
+	}
+	if got[0].ID != "6" || got[1].ID != "4" {
+		t.Fatalf("esperava falhas 6 e 4 (mais recentes primeiro), veio %s e %s", got[0].ID, got[1].ID)
+	}
+}
@@ -0,0 +1,191 @@
+// Package i18n traduz as mensagens exibidas ao usuário.
+//
+// O texto em português (pt-BR) é a língua de origem e serve de chave do
+// catálogo: o código continua legível e uma mensagem sem tradução cai no
+// original em vez de sumir. Para outros idiomas existe um catálogo por idioma
+// (ver en.go) que mapeia a mensagem original para a traduzida.
+package i18n
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Language identifica um idioma da interface.
+type Language string
+
+const (
+	PortugueseBR Language = "pt-BR"
+	English      Language = "en"
+)
+
+// Default é o idioma usado quando nada foi configurado nem detectado.
+const Default = PortugueseBR
+
+// Languages lista os idiomas suportados, na ordem em que são oferecidos.
+var Languages = []Language{PortugueseBR, English}
+
+var catalogs = map[Language]map[string]string{
+	English: english,
+}
+
+var (
+	mu      sync.RWMutex
+	current = Default
+	catalog map[string]string
+)
+
+// SetLanguage troca o idioma das mensagens. Retorna false se o idioma não é
+// suportado, mantendo o atual.
+func SetLanguage(lang Language) bool {
+	if !Supported(lang) {
+		return false
+	}
+	mu.Lock()
+	current = lang
+	catalog = catalogs[lang]
+	mu.Unlock()
+	return true
+}
+
+// Current retorna o idioma em uso.
+func Current() Language {
+	mu.RLock()
+	defer mu.RUnlock()
+	return current
+}
+
+// Supported informa se há catálogo para o idioma.
+func Supported(lang Language) bool {
+	for _, l := range Languages {
+		if l == lang {
+			return true
+		}
+	}
+	return false
+}
+
+// Parse converte um código de idioma ou locale ("en", "en_US.UTF-8", "pt-BR")
+// para um idioma suportado. Retorna false para vazio, "C"/"POSIX" e idiomas
+// sem catálogo.
+func Parse(code string) (Language, bool) {
+	code = strings.TrimSpace(code)
+	// Remove codificação e modificador: "pt_BR.UTF-8@euro" -> "pt_BR".
+	if i := strings.IndexAny(code, ".@"); i >= 0 {
+		code = code[:i]
+	}
+	base := strings.ToLower(strings.SplitN(strings.ReplaceAll(code, "_", "-"), "-", 2)[0])
+
+	switch base {
+	case "pt":
+		return PortugueseBR, true
+	case "en":
+		return English, true
+	}
+	return "", false
+}
+
+// Detect escolhe o idioma: o configurado, se suportado; senão o do ambiente
+// (LC_ALL, LC_MESSAGES e LANG, nessa ordem, como no POSIX); senão o padrão.
+func Detect(configured string) Language {
+	if lang, ok := Parse(configured); ok {
+		return lang
+	}
+	for _, env := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		v := os.Getenv(env)
+		if v == "" {
+			continue
+		}
+		// A primeira variável definida vence, mesmo que não seja suportada.
+		if lang, ok := Parse(v); ok {
+			return lang
+		}
+		break
+	}
+	return Default
+}
+
+// T traduz a mensagem para o idioma atual.
+func T(msg string) string {
+	mu.RLock()
+	defer mu.RUnlock()
+	if tr, ok := catalog[msg]; ok {
+		return tr
+	}
+	return msg
+}
+
+// Sprintf traduz o formato e o aplica aos argumentos.
+func Sprintf(format string, args ...any) string {
+	return fmt.Sprintf(T(format), args...)
+}
+
+// Printf traduz o formato e escreve em stdout.
+func Printf(format string, args ...any) {
+	fmt.Printf(T(format), args...)
+}
+
+// Print traduz a mensagem e escreve em stdout.
+func Print(msg string) {
+	fmt.Print(T(msg))
+}
+
+// Println traduz a mensagem e escreve em stdout com quebra de linha.
+func Println(msg string) {
+	fmt.Println(T(msg))
+}
+
+// Fprintf traduz o formato e escreve em w.
+func Fprintf(w io.Writer, format string, args ...any) {
+	fmt.Fprintf(w, T(format), args...)
+}
+
+// Fprintln traduz a mensagem e escreve em w com quebra de linha.
+func Fprintln(w io.Writer, msg string) {
+	fmt.Fprintln(w, T(msg))
+}
+
+// Errorf é o fmt.Errorf com o formato traduzido; suporta %w.
+func Errorf(format string, args ...any) error {
+	return fmt.Errorf(T(format), args...)
+}
+
+// message é um erro cuja mensagem é traduzida no momento da exibição, para
+// erros criados antes de o idioma ser definido (sentinelas de pacote, leitura
+// da configuração).
+type message struct {
+	format string
+	args   []any
+}
+
+func (m *message) Error() string {
+	if len(m.args) == 0 {
+		return T(m.format)
+	}
+	return fmt.Errorf(T(m.format), m.args...).Error()
+}
+
+// Unwrap expõe os erros passados como argumento, como faz fmt.Errorf com %w.
+func (m *message) Unwrap() []error {
+	var errs []error
+	for _, a := range m.args {
+		if err, ok := a.(error); ok {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// NewError cria um erro sentinela com mensagem traduzível. Como errors.New,
+// cada chamada retorna um valor distinto.
+func NewError(msg string) error {
+	return &message{format: msg}
+}
+
+// LazyErrorf é como Errorf, mas traduz e formata a mensagem só ao exibir.
+func LazyErrorf(format string, args ...any) error {
+	return &message{format: format, args: args}
+}
@@ -0,0 +1,154 @@
+package i18n
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	cases := map[string]Language{
+		"en":          English,
+		"en_US.UTF-8": English,
+		"en-GB":       English,
+		"pt_BR.UTF-8": PortugueseBR,
+		"pt-PT":       PortugueseBR,
+		"PT_br@euro":  PortugueseBR,
+	}
+	for in, want := range cases {
+		if got, ok := Parse(in); !ok || got != want {
+			t.Errorf("Parse(%q) = %q, %v; esperava %q", in, got, ok, want)
+		}
+	}
+	for _, in := range []string{"", "C", "POSIX", "de_DE.UTF-8"} {
+		if got, ok := Parse(in); ok {
+			t.Errorf("Parse(%q) deveria falhar, veio %q", in, got)
+		}
+	}
+}
+
+func TestDetect(t *testing.T) {
+	t.Setenv("LC_ALL", "")
+	t.Setenv("LC_MESSAGES", "")
+	t.Setenv("LANG", "en_US.UTF-8")
+
+	if got := Detect(""); got != English {
+		t.Errorf("LANG=en_US deveria resultar em inglês, veio %q", got)
+	}
+	if got := Detect("pt-BR"); got != PortugueseBR {
+		t.Errorf("configuração deveria vencer o ambiente, veio %q", got)
+	}
+
+	t.Setenv("LC_ALL", "de_DE.UTF-8")
+	if got := Detect(""); got != Default {
+		t.Errorf("LC_ALL sem catálogo deveria usar o padrão, veio %q", got)
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	defer SetLanguage(Current())
+
+	SetLanguage(English)
+	if got := T("Opção inválida!"); got != "Invalid option!" {
+		t.Errorf("T = %q", got)
+	}
+	if got := T("mensagem sem tradução"); got != "mensagem sem tradução" {
+		t.Errorf("mensagem sem tradução deveria voltar o original, veio %q", got)
+	}
+
+	err := NewError("download cancelado")
+	if got := err.Error(); got != "download canceled" {
+		t.Errorf("NewError deveria traduzir ao exibir, veio %q", got)
+	}
+	SetLanguage(PortugueseBR)
+	if got := err.Error(); got != "download cancelado" {
+		t.Errorf("NewError deveria acompanhar o idioma atual, veio %q", got)
+	}
+}
+
+var verbRegex = regexp.MustCompile(`%[-+# 0-9.*]*[a-zA-Z%]`)
+
+// TestEnglishCatalogCoversSource garante que toda mensagem passada às funções
+// do pacote tenha tradução, com os mesmos verbos de formatação, e que o
+// catálogo não acumule mensagens que saíram do código.
+func TestEnglishCatalogCoversSource(t *testing.T) {
+	funcs := map[string]bool{
+		"T": true, "Sprintf": true, "Printf": true, "Print": true, "Println": true,
+		"Fprintf": true, "Fprintln": true, "Errorf": true, "NewError": true, "LazyErrorf": true,
+	}
+
+	root := filepath.Join("..", "..")
+	fset := token.NewFileSet()
+	used := make(map[string]bool)
+	var missing []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if strings.HasPrefix(d.Name(), ".") && path != root {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+			return nil
+		}
+
+		file, err := parser.ParseFile(fset, path, nil, 0)
+		if err != nil {
+			return err
+		}
+		ast.Inspect(file, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			sel, ok := call.Fun.(*ast.SelectorExpr)
+			if !ok || !funcs[sel.Sel.Name] {
+				return true
+			}
+			if pkg, ok := sel.X.(*ast.Ident); !ok || pkg.Name != "i18n" {
+				return true
+			}
+
+			for _, arg := range call.Args {
+				lit, ok := arg.(*ast.BasicLit)
+				if !ok || lit.Kind != token.STRING {
+					continue
+				}
+				msg, _ := strconv.Unquote(lit.Value)
+				used[msg] = true
+				tr, ok := english[msg]
+				switch {
+				case !ok:
+					missing = append(missing, fset.Position(lit.Pos()).String()+": "+lit.Value)
+				case !slices.Equal(verbRegex.FindAllString(msg, -1), verbRegex.FindAllString(tr, -1)):
+					t.Errorf("%s: verbos diferentes na tradução de %q: %q", fset.Position(lit.Pos()), msg, tr)
+				}
+				break
+			}
+			return true
+		})
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, m := range missing {
+		t.Errorf("sem tradução em inglês: %s", m)
+	}
+	for msg := range english {
+		if !used[msg] {
+			t.Errorf("tradução sem uso no código: %q", msg)
+		}
+	}
+}
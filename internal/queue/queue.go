@@ -0,0 +1,312 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/diogocardoso/DownloaderTube/internal/downloader"
+)
+
+// State representa a etapa em que um job da fila se encontra.
+type State int
+
+const (
+	StatePending State = iota
+	StateRunning
+	StateDone
+	StateFailed
+	StateCanceled
+)
+
+// Job descreve um download enfileirado. Os campos exportados são definidos
+// antes de Add e não devem ser alterados depois.
+type Job struct {
+	Request    downloader.DownloadRequest
+	Title      string
+	Label      string
+	Downloader downloader.Downloader
+	// RetryOf é o ID no histórico da tentativa repetida por este job, se houver.
+	RetryOf string
+
+	id int
+
+	mu         sync.Mutex
+	cancel     context.CancelFunc
+	canceled   bool
+	state      State
+	progress   downloader.ProgressEvent
+	result     downloader.DownloadResult
+	err        error
+	startedAt  time.Time
+	finishedAt time.Time
+}
+
+// Status é uma cópia consistente do estado de um job para exibição.
+type Status struct {
+	ID         int
+	Title      string
+	Label      string
+	URL        string
+	State      State
+	Progress   downloader.ProgressEvent
+	Result     downloader.DownloadResult
+	Err        error
+	StartedAt  time.Time
+	FinishedAt time.Time
+}
+
+func (j *Job) status() Status {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return Status{
+		ID:         j.id,
+		Title:      j.Title,
+		Label:      j.Label,
+		URL:        j.Request.URL,
+		State:      j.state,
+		Progress:   j.progress,
+		Result:     j.result,
+		Err:        j.err,
+		StartedAt:  j.startedAt,
+		FinishedAt: j.finishedAt,
+	}
+}
+
+func (j *Job) setProgress(ev downloader.ProgressEvent) {
+	j.mu.Lock()
+	j.progress = ev
+	j.mu.Unlock()
+}
+
+// Queue executa downloads em paralelo com um número fixo de workers.
+// Jobs podem ser adicionados a qualquer momento, inclusive com outros em execução.
+type Queue struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	pending []*Job
+	jobs    []*Job
+	nextID  int
+	workers int
+	closed  bool
+	wg      sync.WaitGroup
+
+	// OnFinish, quando definido, é chamado ao fim de cada job (sucesso ou falha).
+	OnFinish func(j *Job, s Status)
+}
+
+// New cria a fila e inicia os workers. Valores menores que 1 usam um único worker.
+func New(workers int) *Queue {
+	if workers < 1 {
+		workers = 1
+	}
+	q := &Queue{workers: workers}
+	q.cond = sync.NewCond(&q.mu)
+
+	q.wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go q.worker()
+	}
+	return q
+}
+
+// Workers retorna a quantidade de downloads simultâneos da fila.
+func (q *Queue) Workers() int {
+	return q.workers
+}
+
+// Add enfileira o job e retorna seu identificador.
+func (q *Queue) Add(j *Job) int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.nextID++
+	j.id = q.nextID
+	j.state = StatePending
+	q.jobs = append(q.jobs, j)
+	q.pending = append(q.pending, j)
+	q.cond.Signal()
+	return j.id
+}
+
+// Snapshot retorna o estado atual de todos os jobs, na ordem em que foram adicionados.
+func (q *Queue) Snapshot() []Status {
+	q.mu.Lock()
+	jobs := append([]*Job(nil), q.jobs...)
+	q.mu.Unlock()
+
+	out := make([]Status, 0, len(jobs))
+	for _, j := range jobs {
+		out = append(out, j.status())
+	}
+	return out
+}
+
+// Active retorna quantos jobs ainda estão aguardando ou em execução.
+func (q *Queue) Active() int {
+	n := 0
+	for _, s := range q.Snapshot() {
+		if s.State == StatePending || s.State == StateRunning {
+			n++
+		}
+	}
+	return n
+}
+
+// Cancel interrompe o job: se ainda estiver aguardando, ele sai da fila sem
+// iniciar; se estiver em execução, o download é cancelado e os arquivos
+// parciais são removidos. Retorna false se o job não existe ou já terminou.
+func (q *Queue) Cancel(id int) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for _, j := range q.jobs {
+		if j.id == id {
+			return q.cancelLocked(j)
+		}
+	}
+	return false
+}
+
+// CancelAll cancela todos os jobs aguardando ou em execução e retorna quantos
+// foram afetados.
+func (q *Queue) CancelAll() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	n := 0
+	for _, j := range q.jobs {
+		if q.cancelLocked(j) {
+			n++
+		}
+	}
+	return n
+}
+
+func (q *Queue) cancelLocked(j *Job) bool {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	switch j.state {
+	case StatePending:
+		for i, p := range q.pending {
+			if p == j {
+				q.pending = append(q.pending[:i], q.pending[i+1:]...)
+				break
+			}
+		}
+		j.state = StateCanceled
+		j.err = context.Canceled
+		j.finishedAt = time.Now()
+		return true
+	case StateRunning:
+		if j.canceled {
+			return false
+		}
+		j.canceled = true
+		j.cancel()
+		return true
+	}
+	return false
+}
+
+// ClearFinished remove da listagem os jobs concluídos, com falha ou cancelados.
+func (q *Queue) ClearFinished() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	kept := q.jobs[:0]
+	for _, j := range q.jobs {
+		j.mu.Lock()
+		finished := j.state == StateDone || j.state == StateFailed || j.state == StateCanceled
+		j.mu.Unlock()
+		if !finished {
+			kept = append(kept, j)
+		}
+	}
+	q.jobs = kept
+}
+
+// Close impede novos jobs de serem iniciados e aguarda os que estão em execução.
+func (q *Queue) Close() {
+	q.mu.Lock()
+	q.closed = true
+	q.cond.Broadcast()
+	q.mu.Unlock()
+	q.wg.Wait()
+}
+
+// next tira o próximo job da fila e já o marca como em execução, ainda com
+// q.mu travado: assim um Cancel entre a retirada e o início cancela o
+// contexto do job em vez de encontrá-lo fora da fila e ainda pendente.
+func (q *Queue) next() (*Job, context.Context) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for len(q.pending) == 0 && !q.closed {
+		q.cond.Wait()
+	}
+	if q.closed {
+		return nil, nil
+	}
+	j := q.pending[0]
+	q.pending = q.pending[1:]
+
+	ctx, cancel := context.WithCancel(context.Background())
+	j.mu.Lock()
+	j.state = StateRunning
+	j.startedAt = time.Now()
+	j.cancel = cancel
+	j.mu.Unlock()
+	return j, ctx
+}
+
+func (q *Queue) worker() {
+	defer q.wg.Done()
+	for {
+		j, ctx := q.next()
+		if j == nil {
+			return
+		}
+		q.run(ctx, j)
+	}
+}
+
+func (q *Queue) run(ctx context.Context, j *Job) {
+	j.mu.Lock()
+	cancel := j.cancel
+	canceled := j.canceled
+	if canceled {
+		// Cancelado antes de começar: não baixa nem registra resultado,
+		// como um job cancelado enquanto aguardava.
+		j.state = StateCanceled
+		j.err = context.Canceled
+		j.finishedAt = time.Now()
+	}
+	j.mu.Unlock()
+	defer cancel()
+	if canceled {
+		return
+	}
+
+	result, err := j.Downloader.Download(ctx, j.Request, j.setProgress)
+
+	j.mu.Lock()
+	j.result = result
+	j.err = err
+	j.finishedAt = time.Now()
+	switch {
+	case err == nil:
+		j.state = StateDone
+	case errors.Is(err, context.Canceled):
+		j.state = StateCanceled
+	default:
+		j.state = StateFailed
+	}
+	j.mu.Unlock()
+
+	if q.OnFinish != nil {
+		q.OnFinish(j, j.status())
+	}
+}
@@ -0,0 +1,160 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/diogocardoso/DownloaderTube/internal/downloader"
+)
+
+type fakeDownloader struct {
+	mu      sync.Mutex
+	running int
+	peak    int
+}
+
+func (f *fakeDownloader) GetVideoInfo(ctx context.Context, url string) (*downloader.VideoInfo, error) {
+	return &downloader.VideoInfo{}, nil
+}
+
+func (f *fakeDownloader) Download(ctx context.Context, req downloader.DownloadRequest, progress downloader.ProgressFunc) (downloader.DownloadResult, error) {
+	f.mu.Lock()
+	f.running++
+	if f.running > f.peak {
+		f.peak = f.running
+	}
+	f.mu.Unlock()
+
+	progress(downloader.ProgressEvent{Phase: downloader.PhaseDownloading, Downloaded: 50, Total: 100, Percent: 50})
+	delay := 20 * time.Millisecond
+	if req.URL == "lento" {
+		delay = 5 * time.Second
+	}
+	select {
+	case <-time.After(delay):
+	case <-ctx.Done():
+	}
+
+	f.mu.Lock()
+	f.running--
+	f.mu.Unlock()
+
+	if ctx.Err() != nil {
+		return downloader.DownloadResult{}, ctx.Err()
+	}
+	if req.URL == "falha" {
+		return downloader.DownloadResult{}, errors.New("erro simulado")
+	}
+	return downloader.DownloadResult{FilePath: req.URL + ".mp4"}, nil
+}
+
+func waitIdle(t *testing.T, q *Queue) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for q.Active() > 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("fila não terminou a tempo: %+v", q.Snapshot())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestQueueRespectsWorkerLimit(t *testing.T) {
+	dl := &fakeDownloader{}
+	q := New(2)
+	defer q.Close()
+
+	for _, u := range []string{"a", "b", "c", "d", "falha"} {
+		q.Add(&Job{Request: downloader.DownloadRequest{URL: u}, Downloader: dl})
+	}
+	waitIdle(t, q)
+
+	if dl.peak > 2 {
+		t.Fatalf("esperava no máximo 2 downloads simultâneos, veio %d", dl.peak)
+	}
+
+	jobs := q.Snapshot()
+	if len(jobs) != 5 {
+		t.Fatalf("esperava 5 jobs, veio %d", len(jobs))
+	}
+	for _, s := range jobs {
+		switch {
+		case s.URL == "falha" && s.State != StateFailed:
+			t.Fatalf("job %d deveria ter falhado: %+v", s.ID, s)
+		case s.URL != "falha" && (s.State != StateDone || s.Result.FilePath != s.URL+".mp4"):
+			t.Fatalf("job %d deveria ter concluído: %+v", s.ID, s)
+		}
+	}
+
+	q.ClearFinished()
+	if got := q.Snapshot(); len(got) != 0 {
+		t.Fatalf("ClearFinished deveria remover todos, restaram %d", len(got))
+	}
+}
+
+func TestQueueCancel(t *testing.T) {
+	dl := &fakeDownloader{}
+	q := New(1)
+	defer q.Close()
+
+	running := q.Add(&Job{Request: downloader.DownloadRequest{URL: "lento"}, Downloader: dl})
+	pending := q.Add(&Job{Request: downloader.DownloadRequest{URL: "a"}, Downloader: dl})
+
+	deadline := time.Now().Add(2 * time.Second)
+	for q.Snapshot()[0].State != StateRunning {
+		if time.Now().After(deadline) {
+			t.Fatal("job não começou a tempo")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	if !q.Cancel(pending) {
+		t.Fatal("Cancel deveria aceitar job aguardando")
+	}
+	if !q.Cancel(running) {
+		t.Fatal("Cancel deveria aceitar job em execução")
+	}
+	waitIdle(t, q)
+
+	for _, s := range q.Snapshot() {
+		if s.State != StateCanceled {
+			t.Fatalf("job %d deveria estar cancelado: %+v", s.ID, s)
+		}
+	}
+	if q.Cancel(running) {
+		t.Fatal("Cancel não deveria aceitar job já terminado")
+	}
+}
+
+func TestQueueCancelBetweenDequeueAndStart(t *testing.T) {
+	// Fila sem workers, para parar exatamente entre next e run.
+	q := &Queue{workers: 1}
+	q.cond = sync.NewCond(&q.mu)
+	finished := false
+	q.OnFinish = func(*Job, Status) { finished = true }
+
+	dl := &fakeDownloader{}
+	id := q.Add(&Job{Request: downloader.DownloadRequest{URL: "a"}, Downloader: dl})
+
+	j, ctx := q.next()
+	if !q.Cancel(id) {
+		t.Fatal("Cancel deveria aceitar job retirado da fila")
+	}
+	q.run(ctx, j)
+
+	if dl.peak != 0 {
+		t.Fatal("job cancelado antes de começar não deveria ser baixado")
+	}
+	if s := j.status(); s.State != StateCanceled {
+		t.Fatalf("job deveria estar cancelado: %+v", s)
+	}
+	if finished {
+		t.Fatal("OnFinish não deveria registrar job cancelado antes de começar")
+	}
+	if q.Cancel(id) {
+		t.Fatal("Cancel não deveria aceitar job já cancelado")
+	}
+}
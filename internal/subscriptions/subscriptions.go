@@ -0,0 +1,217 @@
+// Package subscriptions guarda os canais e perfis acompanhados, baixados
+// periodicamente pelo comando "sync".
+package subscriptions
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/diogocardoso/DownloaderTube/internal/i18n"
+)
+
+// Source é um canal do YouTube ou perfil do Instagram acompanhado.
+type Source struct {
+	ID       string `json:"id"`
+	URL      string `json:"url"`
+	Platform string `json:"platform"`
+	// Name é um apelido para exibição; vazio usa a URL.
+	Name string `json:"name,omitempty"`
+	// Quality é um preset (best, 720p, whatsapp), uma altura máxima (ex: 1080)
+	// ou um formato de áudio (mp3, m4a, opus); vazio usa o padrão da configuração.
+	Quality string `json:"quality,omitempty"`
+	// Since ignora itens publicados antes desta data (AAAA-MM-DD).
+	Since string `json:"since,omitempty"`
+	// Limit é o máximo de itens verificados por sincronização; 0 = sem limite.
+	Limit   int       `json:"limit,omitempty"`
+	AddedAt time.Time `json:"added_at"`
+	// LastSync, LastCount e LastError registram a última sincronização.
+	LastSync  time.Time `json:"last_sync"`
+	LastCount int       `json:"last_count,omitempty"`
+	LastError string    `json:"last_error,omitempty"`
+}
+
+// Label retorna o apelido da fonte, ou a URL quando não houver.
+func (s Source) Label() string {
+	if s.Name != "" {
+		return s.Name
+	}
+	return s.URL
+}
+
+// SinceDate interpreta Since; zero quando não há filtro de data.
+func (s Source) SinceDate() (time.Time, error) {
+	return ParseDate(s.Since)
+}
+
+// ParseDate interpreta uma data AAAA-MM-DD; vazio retorna zero.
+func ParseDate(raw string) (time.Time, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", raw, time.Local)
+	if err != nil {
+		return time.Time{}, i18n.Errorf("data inválida %q (use AAAA-MM-DD)", raw)
+	}
+	return t, nil
+}
+
+type fileFormat struct {
+	Version int      `json:"version"`
+	Sources []Source `json:"sources"`
+}
+
+const fileVersion = 1
+
+// Store persiste as assinaturas em um arquivo JSON. É seguro para uso
+// concorrente dentro do mesmo processo.
+type Store struct {
+	path string
+	mu   sync.Mutex
+	seq  int64
+}
+
+// DefaultPath retorna o caminho padrão das assinaturas no diretório de configuração do usuário.
+func DefaultPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", i18n.Errorf("não foi possível determinar diretório de configuração: %w", err)
+	}
+	return filepath.Join(dir, "DownloaderTube", "subscriptions.json"), nil
+}
+
+// Open cria um Store para o arquivo informado. O arquivo é criado no primeiro Add.
+func Open(path string) *Store {
+	return &Store{path: path}
+}
+
+// Path retorna o caminho do arquivo de assinaturas.
+func (s *Store) Path() string {
+	return s.path
+}
+
+// ArchivePath retorna o arquivo --download-archive compartilhado pelas
+// assinaturas, ao lado do arquivo de assinaturas.
+func (s *Store) ArchivePath() string {
+	return filepath.Join(filepath.Dir(s.path), "archive.txt")
+}
+
+// List retorna as assinaturas na ordem em que foram adicionadas.
+func (s *Store) List() ([]Source, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.load()
+}
+
+// Add grava uma nova assinatura e a retorna com ID e data preenchidos. A
+// mesma URL não pode ser assinada duas vezes.
+func (s *Store) Add(src Source) (Source, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sources, err := s.load()
+	if err != nil {
+		return src, err
+	}
+	for _, existing := range sources {
+		if strings.EqualFold(strings.TrimRight(existing.URL, "/"), strings.TrimRight(src.URL, "/")) {
+			return src, i18n.Errorf("%s já está nas assinaturas", src.URL)
+		}
+	}
+
+	s.seq++
+	src.ID = strconv.FormatInt(time.Now().UnixNano(), 36) + strconv.FormatInt(s.seq, 36)
+	if src.AddedAt.IsZero() {
+		src.AddedAt = time.Now()
+	}
+	sources = append(sources, src)
+	return src, s.save(sources)
+}
+
+// Update substitui a assinatura de mesmo ID.
+func (s *Store) Update(src Source) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sources, err := s.load()
+	if err != nil {
+		return err
+	}
+	for i := range sources {
+		if sources[i].ID == src.ID {
+			sources[i] = src
+			return s.save(sources)
+		}
+	}
+	return i18n.Errorf("assinatura %s não encontrada", src.ID)
+}
+
+// Remove apaga a assinatura com o ID informado. O arquivo de downloads já
+// registrados é mantido.
+func (s *Store) Remove(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sources, err := s.load()
+	if err != nil {
+		return err
+	}
+	kept := sources[:0]
+	found := false
+	for _, src := range sources {
+		if src.ID == id {
+			found = true
+			continue
+		}
+		kept = append(kept, src)
+	}
+	if !found {
+		return i18n.Errorf("assinatura %s não encontrada", id)
+	}
+	return s.save(kept)
+}
+
+func (s *Store) load() ([]Source, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, i18n.Errorf("erro ao ler assinaturas: %w", err)
+	}
+
+	var f fileFormat
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, i18n.Errorf("erro ao parsear assinaturas: %w", err)
+	}
+	return f.Sources, nil
+}
+
+// save grava em arquivo temporário e renomeia, para não corromper as
+// assinaturas se o processo for interrompido no meio da escrita.
+func (s *Store) save(sources []Source) error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return i18n.Errorf("erro ao criar diretório das assinaturas: %w", err)
+	}
+
+	data, err := json.MarshalIndent(fileFormat{Version: fileVersion, Sources: sources}, "", "  ")
+	if err != nil {
+		return i18n.Errorf("erro ao serializar assinaturas: %w", err)
+	}
+
+	tmpPath := s.path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0o644); err != nil {
+		return i18n.Errorf("erro ao gravar assinaturas: %w", err)
+	}
+	if err := os.Rename(tmpPath, s.path); err != nil {
+		os.Remove(tmpPath)
+		return i18n.Errorf("erro ao salvar assinaturas: %w", err)
+	}
+	return nil
+}
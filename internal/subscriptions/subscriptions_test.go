@@ -0,0 +1,58 @@
+package subscriptions
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestStoreAddUpdateRemove(t *testing.T) {
+	s := Open(filepath.Join(t.TempDir(), "sub", "subscriptions.json"))
+
+	canal, err := s.Add(Source{URL: "https://www.youtube.com/@canal/videos", Platform: "youtube", Quality: "720p"})
+	if err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if canal.ID == "" || canal.AddedAt.IsZero() {
+		t.Fatalf("Add deveria preencher ID e data: %+v", canal)
+	}
+	if _, err := s.Add(Source{URL: "https://www.youtube.com/@Canal/videos/", Platform: "youtube"}); err == nil {
+		t.Fatalf("a mesma URL não deveria ser assinada duas vezes")
+	}
+	if _, err := s.Add(Source{URL: "https://www.instagram.com/perfil/", Platform: "instagram", Name: "Perfil"}); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+
+	canal.LastCount = 3
+	if err := s.Update(canal); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	all, err := s.List()
+	if err != nil || len(all) != 2 {
+		t.Fatalf("List = %d assinaturas, err=%v", len(all), err)
+	}
+	if all[0].LastCount != 3 || all[1].Label() != "Perfil" {
+		t.Fatalf("assinaturas inesperadas: %+v", all)
+	}
+
+	if err := s.Remove(canal.ID); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+	if err := s.Remove(canal.ID); err == nil {
+		t.Fatalf("Remove repetido deveria falhar")
+	}
+	if got := s.ArchivePath(); filepath.Base(got) != "archive.txt" || filepath.Dir(got) != filepath.Dir(s.Path()) {
+		t.Fatalf("ArchivePath inesperado: %s", got)
+	}
+}
+
+func TestParseDate(t *testing.T) {
+	if d, err := ParseDate(""); err != nil || !d.IsZero() {
+		t.Errorf("data vazia deveria ser zero: %v, %v", d, err)
+	}
+	if d, err := ParseDate("2026-03-01"); err != nil || d.Day() != 1 || d.Month() != 3 {
+		t.Errorf("ParseDate = %v, %v", d, err)
+	}
+	if _, err := ParseDate("01/03/2026"); err == nil {
+		t.Errorf("formato errado deveria falhar")
+	}
+}
@@ -0,0 +1,125 @@
+package validator
+
+import (
+	"net/url"
+	"strings"
+)
+
+// Platform identifica a plataforma de origem de uma URL.
+type Platform string
+
+const (
+	PlatformUnknown   Platform = ""
+	PlatformYouTube   Platform = "youtube"
+	PlatformFacebook  Platform = "facebook"
+	PlatformInstagram Platform = "instagram"
+)
+
+// Platforms lista as plataformas suportadas, na ordem exibida nos menus.
+var Platforms = []Platform{PlatformYouTube, PlatformFacebook, PlatformInstagram}
+
+// Label retorna o nome da plataforma para exibição.
+func (p Platform) Label() string {
+	switch p {
+	case PlatformYouTube:
+		return "YouTube"
+	case PlatformFacebook:
+		return "Facebook"
+	case PlatformInstagram:
+		return "Instagram"
+	default:
+		return "Desconhecida"
+	}
+}
+
+// Match é uma interpretação possível de uma URL colada pelo usuário.
+type Match struct {
+	Platform Platform
+	URL      string
+}
+
+// redirectParams são parâmetros de query usados por links de redirecionamento
+// (ex: l.facebook.com/l.php?u=..., youtube.com/redirect?q=...).
+var redirectParams = []string{"u", "url", "q", "link"}
+
+// Classify normaliza a URL e retorna as plataformas que podem atendê-la.
+// Nenhum resultado indica URL não suportada; mais de um indica ambiguidade
+// (ex: link do Facebook que aponta para um vídeo do YouTube).
+func Classify(raw string) []Match {
+	normalized := NormalizeURL(raw)
+	if normalized == "" {
+		return nil
+	}
+
+	var matches []Match
+	if p := DetectPlatform(normalized); p != PlatformUnknown {
+		matches = append(matches, Match{Platform: p, URL: normalized})
+	}
+
+	u, err := url.Parse(normalized)
+	if err != nil {
+		return matches
+	}
+	query := u.Query()
+	for _, key := range redirectParams {
+		inner := NormalizeURL(query.Get(key))
+		if inner == "" {
+			continue
+		}
+		p := DetectPlatform(inner)
+		if p == PlatformUnknown || containsPlatform(matches, p) {
+			continue
+		}
+		matches = append(matches, Match{Platform: p, URL: inner})
+	}
+
+	return matches
+}
+
+// NormalizeURL remove espaços e completa o esquema quando o usuário cola
+// apenas "youtube.com/watch?v=...". Retorna "" quando não parece uma URL.
+func NormalizeURL(raw string) string {
+	raw = strings.TrimSpace(raw)
+	if raw == "" || strings.ContainsAny(raw, " \t") {
+		return ""
+	}
+	if !strings.Contains(raw, "://") {
+		if !strings.Contains(raw, ".") {
+			return ""
+		}
+		raw = "https://" + raw
+	}
+	if _, err := url.ParseRequestURI(raw); err != nil {
+		return ""
+	}
+	return raw
+}
+
+// LooksLikeURL indica se a entrada deve ser tratada como link e não como opção de menu.
+func LooksLikeURL(raw string) bool {
+	return NormalizeURL(raw) != ""
+}
+
+// DetectPlatform classifica a URL de acordo com a plataforma reconhecida.
+// Retorna PlatformUnknown quando nenhum validador aceitar a URL.
+func DetectPlatform(raw string) Platform {
+	switch {
+	case IsYouTubeURL(raw):
+		return PlatformYouTube
+	case IsFacebookURL(raw):
+		return PlatformFacebook
+	case IsInstagramURL(raw):
+		return PlatformInstagram
+	default:
+		return PlatformUnknown
+	}
+}
+
+func containsPlatform(matches []Match, p Platform) bool {
+	for _, m := range matches {
+		if m.Platform == p {
+			return true
+		}
+	}
+	return false
+}
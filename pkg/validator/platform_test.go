@@ -0,0 +1,90 @@
+package validator
+
+import "testing"
+
+func TestClassifySinglePlatform(t *testing.T) {
+	cases := map[string]Platform{
+		"https://www.youtube.com/watch?v=abc":    PlatformYouTube,
+		"youtu.be/abc":                           PlatformYouTube,
+		"  https://fb.watch/xyz/  ":              PlatformFacebook,
+		"www.instagram.com/reel/C0de/":           PlatformInstagram,
+		"https://m.facebook.com/watch/?v=123456": PlatformFacebook,
+	}
+
+	for raw, want := range cases {
+		got := Classify(raw)
+		if len(got) != 1 || got[0].Platform != want {
+			t.Fatalf("Classify(%q) = %+v, esperava apenas %s", raw, got, want)
+		}
+	}
+}
+
+func TestClassifyUnsupported(t *testing.T) {
+	for _, raw := range []string{"", "1", "x", "https://vimeo.com/123", "nao e url"} {
+		if got := Classify(raw); len(got) != 0 {
+			t.Fatalf("Classify(%q) deveria ser vazio, veio %+v", raw, got)
+		}
+	}
+}
+
+func TestClassifyRedirectIsAmbiguous(t *testing.T) {
+	raw := "https://www.facebook.com/l.php?u=https%3A%2F%2Fyoutu.be%2Fabc"
+	got := Classify(raw)
+	if len(got) != 2 {
+		t.Fatalf("esperava 2 candidatos, veio %+v", got)
+	}
+	if got[0].Platform != PlatformFacebook || got[1].Platform != PlatformYouTube {
+		t.Fatalf("candidatos inesperados: %+v", got)
+	}
+	if got[1].URL != "https://youtu.be/abc" {
+		t.Fatalf("URL interna inesperada: %q", got[1].URL)
+	}
+}
+
+func TestClassifyUnwrapsUnknownRedirect(t *testing.T) {
+	got := Classify("https://l.facebook.com/l.php?u=https%3A%2F%2Fwww.instagram.com%2Fp%2FXYZ%2F")
+	if len(got) != 1 || got[0].Platform != PlatformInstagram {
+		t.Fatalf("esperava apenas Instagram, veio %+v", got)
+	}
+}
+
+func TestIsYouTubePlaylistURL(t *testing.T) {
+	cases := map[string]bool{
+		"https://www.youtube.com/playlist?list=PL123":    true,
+		"https://m.youtube.com/playlist/?list=PL123":     true,
+		"https://www.youtube.com/watch?v=abc&list=PL123": false,
+		"https://www.youtube.com/playlist":               false,
+		"https://www.facebook.com/playlist?list=PL123":   false,
+	}
+	for raw, want := range cases {
+		if got := IsYouTubePlaylistURL(raw); got != want {
+			t.Errorf("IsYouTubePlaylistURL(%q) = %v; esperava %v", raw, got, want)
+		}
+	}
+}
+
+func TestInstagramURLKind(t *testing.T) {
+	cases := map[string]InstagramKind{
+		"https://www.instagram.com/p/C0de/":                   InstagramPost,
+		"https://www.instagram.com/reel/C0de/":                InstagramPost,
+		"https://www.instagram.com/reels/C0de/":               InstagramPost,
+		"https://www.instagram.com/usuario/p/C0de/":           InstagramPost,
+		"https://www.instagram.com/stories/usuario/":          InstagramStories,
+		"https://www.instagram.com/stories/usuario/312345/":   InstagramStories,
+		"https://www.instagram.com/stories/highlights/17890/": InstagramHighlight,
+		"https://www.instagram.com/usuario/reels/":            InstagramReels,
+		"https://www.instagram.com/usuario/":                  InstagramProfile,
+		"https://www.instagram.com/usuario?igsh=abc":          InstagramProfile,
+		"https://www.instagram.com/explore/":                  InstagramUnknown,
+		"https://www.instagram.com/":                          InstagramUnknown,
+		"https://www.youtube.com/usuario/":                    InstagramUnknown,
+	}
+	for raw, want := range cases {
+		if got := InstagramURLKind(raw); got != want {
+			t.Errorf("InstagramURLKind(%q) = %q; esperava %q", raw, got, want)
+		}
+	}
+	if IsInstagramCollectionURL("https://www.instagram.com/p/C0de/") || !IsInstagramCollectionURL("https://www.instagram.com/usuario/") {
+		t.Error("só perfis, reels, stories e destaques são coleções")
+	}
+}
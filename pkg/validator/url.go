@@ -28,6 +28,20 @@ func IsYouTubeURL(raw string) bool {
 	return false
 }
 
+// IsYouTubePlaylistURL indica se a URL é a página de uma playlist do YouTube
+// (youtube.com/playlist?list=...). Um vídeo aberto dentro de uma playlist
+// (watch?v=...&list=...) continua sendo tratado como vídeo.
+func IsYouTubePlaylistURL(raw string) bool {
+	if !IsYouTubeURL(raw) {
+		return false
+	}
+	u, err := url.ParseRequestURI(raw)
+	if err != nil {
+		return false
+	}
+	return strings.TrimSuffix(u.Path, "/") == "/playlist" && u.Query().Get("list") != ""
+}
+
 func IsInstagramURL(raw string) bool {
 	u, err := url.ParseRequestURI(raw)
 	if err != nil {
@@ -50,6 +64,84 @@ func IsInstagramURL(raw string) bool {
 	return false
 }
 
+// InstagramKind identifica o tipo de página de uma URL do Instagram.
+type InstagramKind string
+
+const (
+	InstagramUnknown InstagramKind = ""
+	// InstagramPost é um post, reel ou vídeo avulso (/p/, /reel/, /tv/).
+	InstagramPost InstagramKind = "post"
+	// InstagramStories são os stories de um perfil (/stories/<usuário>/).
+	InstagramStories InstagramKind = "stories"
+	// InstagramHighlight é um destaque (/stories/highlights/<id>/).
+	InstagramHighlight InstagramKind = "highlight"
+	// InstagramReels é a aba de reels de um perfil (/<usuário>/reels/).
+	InstagramReels InstagramKind = "reels"
+	// InstagramProfile é o perfil inteiro (/<usuário>/).
+	InstagramProfile InstagramKind = "profile"
+)
+
+// instagramReservedPaths são páginas do site que não são perfis.
+var instagramReservedPaths = map[string]bool{
+	"about": true, "accounts": true, "developer": true, "direct": true,
+	"explore": true, "legal": true, "web": true,
+}
+
+// InstagramURLKind classifica a URL do Instagram pelo caminho. Retorna
+// InstagramUnknown para outras plataformas e páginas não reconhecidas.
+func InstagramURLKind(raw string) InstagramKind {
+	if !IsInstagramURL(raw) {
+		return InstagramUnknown
+	}
+	u, err := url.ParseRequestURI(raw)
+	if err != nil {
+		return InstagramUnknown
+	}
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(segments) == 0 || segments[0] == "" {
+		return InstagramUnknown
+	}
+
+	switch first := strings.ToLower(segments[0]); {
+	case first == "stories":
+		if len(segments) >= 3 && strings.EqualFold(segments[1], "highlights") {
+			return InstagramHighlight
+		}
+		if len(segments) >= 2 {
+			return InstagramStories
+		}
+	case first == "p" || first == "reel" || first == "reels" || first == "tv":
+		if len(segments) >= 2 {
+			return InstagramPost
+		}
+	case instagramReservedPaths[first]:
+		return InstagramUnknown
+	case len(segments) == 1:
+		return InstagramProfile
+	default:
+		// /<usuário>/p/<código>/ e /<usuário>/reel/<código>/ também são posts.
+		switch strings.ToLower(segments[1]) {
+		case "p", "reel", "tv":
+			if len(segments) >= 3 {
+				return InstagramPost
+			}
+		case "reels":
+			return InstagramReels
+		}
+	}
+	return InstagramUnknown
+}
+
+// IsInstagramCollectionURL indica se a URL lista vários itens (perfil, aba de
+// reels, stories ou destaque), baixados como coleção.
+func IsInstagramCollectionURL(raw string) bool {
+	switch InstagramURLKind(raw) {
+	case InstagramStories, InstagramHighlight, InstagramReels, InstagramProfile:
+		return true
+	}
+	return false
+}
+
 func IsFacebookURL(raw string) bool {
 	u, err := url.ParseRequestURI(raw)
 	if err != nil {